@@ -14,23 +14,43 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of helm", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	path := filepath.Join(binPath, fmt.Sprintf("helm/%s/helm", ver))
 	if !fileExists(path) {
+		if err := validateVersion(ver); err != nil {
+			return err
+		}
+
 		c.Logger.Infof("Downloading version %s of helm to path %s", ver, path)
-		err := downloadArtifact(ver, path)
+		err := stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(ver, stagedPath)
+		})
 		if err != nil {
-			c.Logger.WithError(err).Fatal("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of helm", ver)
 	err := changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -42,7 +62,7 @@ func fileExists(path string) bool {
 func downloadArtifact(semver, path string) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -51,7 +71,7 @@ func downloadArtifact(semver, path string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm64"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	// https://get.helm.sh/helm-v${HELM_SEMVER}-${TARGET_OS}-amd64.tar.gz
@@ -61,22 +81,34 @@ func downloadArtifact(semver, path string) error {
 	client := resty.New()
 	resp, err := client.R().SetOutput(fmt.Sprintf("%s.tar.gz", path)).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading helm: %v", err)
+		return fmt.Errorf("%w: error downloading helm: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: helm %s", ErrVersionNotFound, semver)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading helm: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading helm: status code %d", ErrDownloadFailed, resp.StatusCode())
+	}
+
+	// verify archive
+	if err := verifyArchive(url, fmt.Sprintf("%s.tar.gz", path)); err != nil {
+		if rerr := os.Remove(fmt.Sprintf("%s.tar.gz", path)); rerr != nil {
+			c.Logger.WithError(rerr).Error("failed to remove archive file")
+		}
+		return fmt.Errorf("%w: helm: %w", ErrVerifyFailed, err)
 	}
 
 	// extract archive
 	if err := extractTarGz(fmt.Sprintf("%s.tar.gz", path), filepath.Dir(path), targetOS, targetArch); err != nil {
-		return fmt.Errorf("error extracting helm: %v", err)
+		return fmt.Errorf("error extracting helm: %w", err)
 	}
 
 	return nil
 }
 
-// extractTarGz extracts files from the "teleport" directory inside a tar.gz file to a destination directory.
+// extractTarGz extracts files from the "<os>-<arch>" directory inside a tar.gz file to a destination directory.
 func extractTarGz(tarGzPath, destDir string, targetOS string, targetArch string) error {
 	// Open the tar.gz file
 	file, err := os.Open(tarGzPath)
@@ -105,33 +137,15 @@ func extractTarGz(tarGzPath, destDir string, targetOS string, targetArch string)
 			return err
 		}
 
-		// Check if the file is inside the "teleport" directory
-		if strings.HasPrefix(header.Name, fmt.Sprintf("%s-%s", targetOS, targetArch)) {
-			// Construct the full path for the file, excluding the "teleport/" prefix
-			filePath := filepath.Join(destDir, strings.TrimPrefix(header.Name, fmt.Sprintf("%s-%s/", targetOS, targetArch)))
-
-			// Check the type of the file
-			switch header.Typeflag {
-			case tar.TypeDir:
-				// Create the directory
-				if err := os.MkdirAll(filePath, os.FileMode(header.Mode)); err != nil {
-					return err
-				}
-			case tar.TypeReg:
-				// Create the file
-				outFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-				if err != nil {
-					return err
-				}
-				defer outFile.Close()
-
-				// Copy the file data from the tar archive
-				if _, err := io.Copy(outFile, tr); err != nil {
-					return err
-				}
-			default:
-				return fmt.Errorf("unsupported file type: %v", header.Typeflag)
-			}
+		// Only extract what is inside the "<os>-<arch>" directory, without that prefix
+		prefix := fmt.Sprintf("%s-%s/", targetOS, targetArch)
+		name, found := strings.CutPrefix(header.Name, prefix)
+		if !found {
+			continue
+		}
+
+		if err := extractTarEntry(tr, header, destDir, name, strings.TrimPrefix(header.Linkname, prefix)); err != nil {
+			return err
 		}
 	}
 	derr := os.Remove(tarGzPath)
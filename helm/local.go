@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// installedPath returns where VER of helm is installed under BinDir.
+func installedPath(ver string) string {
+	return filepath.Join(c.BinDir, "helm", normalizeVersion(ver), "helm")
+}
+
+// isInstalled reports whether VER of helm is installed under BinDir.
+func isInstalled(ver string) bool {
+	return fileExists(installedPath(ver))
+}
+
+// activeVersion returns the version the helm symlink in SymLinkDir points at, or "" when there is
+// no symlink or it does not point into BinDir.
+func activeVersion() string {
+	target, err := activeLinkTarget()
+	if err != nil {
+		return ""
+	}
+	return binDirVersion(target)
+}
+
+// activeLinkTarget returns where the helm symlink in SymLinkDir points, a relative target is
+// resolved against SymLinkDir.
+func activeLinkTarget() (string, error) {
+	target, err := os.Readlink(filepath.Join(c.SymLinkDir, "helm"))
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(c.SymLinkDir, target)
+	}
+	return filepath.Abs(target)
+}
+
+// binDirVersion returns the version directory under BinDir/helm that TARGET is in, or "" when
+// TARGET is outside it.
+func binDirVersion(target string) string {
+	toolDir, err := filepath.Abs(filepath.Join(c.BinDir, "helm"))
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(toolDir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	ver, _, found := strings.Cut(rel, string(filepath.Separator))
+	if !found {
+		return ""
+	}
+	return ver
+}
+
+// installedVersions returns the versions of helm installed under BinDir, leaving out the cache and
+// staging directories and any version whose binary is missing.
+func installedVersions() ([]string, error) {
+	dirEntries, err := os.ReadDir(filepath.Join(c.BinDir, "helm"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read installed versions: %w", err)
+	}
+
+	versions := []string{}
+	for _, entry := range dirEntries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if isInstalled(entry.Name()) {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions, nil
+}
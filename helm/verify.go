@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-resty/resty/v2"
+)
+
+// helmKeysURL is the KEYS file holding the public keys of the helm release managers.
+const helmKeysURL = "https://raw.githubusercontent.com/helm/helm/main/KEYS"
+
+// verifyArchive checks the archive at PATH against the .sha256sum published next to URL and, when
+// --verify-signature is set, checks the detached .asc signature against the helm KEYS file.
+func verifyArchive(url string, path string) error {
+	sum, err := fetchBytes(fmt.Sprintf("%s.sha256sum", url))
+	if err != nil {
+		return fmt.Errorf("failed to fetch sha256sum: %w", err)
+	}
+
+	// the file is "<sha256>  helm-<v>-<os>-<arch>.tar.gz"
+	fields := strings.Fields(string(sum))
+	if len(fields) == 0 {
+		return fmt.Errorf("empty sha256sum for %s", url)
+	}
+
+	if err := verifyChecksum(path, fields[0]); err != nil {
+		return err
+	}
+
+	if !c.VerifySignature {
+		return nil
+	}
+
+	sig, err := fetchBytes(fmt.Sprintf("%s.asc", url))
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature: %w", err)
+	}
+
+	keyring, err := helmKeyRing()
+	if err != nil {
+		return fmt.Errorf("failed to load the helm KEYS: %w", err)
+	}
+
+	archive, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	signer, err := openpgp.CheckArmoredDetachedSignature(keyring, archive, bytes.NewReader(sig), nil)
+	if err != nil {
+		return fmt.Errorf("bad signature on %s: %w", path, err)
+	}
+	c.Logger.Debugf("%s signed by %X", path, signer.PrimaryKey.Fingerprint)
+
+	return nil
+}
+
+// helmKeyRing reads every armored key in the KEYS file, from --keyring when set or else from
+// the helm repository.
+func helmKeyRing() (openpgp.EntityList, error) {
+	var keys []byte
+	if len(c.KeyringFile) > 0 {
+		c.Logger.Debugf("using keyring from %s", c.KeyringFile)
+		data, err := os.ReadFile(c.KeyringFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = data
+	} else {
+		data, err := fetchBytes(helmKeysURL)
+		if err != nil {
+			return nil, err
+		}
+		keys = data
+	}
+
+	// KEYS holds one armored block per maintainer, interleaved with gpg listing text
+	var keyring openpgp.EntityList
+	reader := bytes.NewReader(keys)
+	for {
+		block, err := armor.Decode(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		entities, err := openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		keyring = append(keyring, entities...)
+	}
+
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("no public keys found")
+	}
+	return keyring, nil
+}
+
+// verifyChecksum checks the file at PATH against the EXPECTED sha256.
+func verifyChecksum(path string, expected string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("sha256 mismatch for %s: expected %s, got %s", path, expected, actual)
+	}
+
+	c.Logger.Debugf("verified %s (sha256 %s)", path, actual)
+	return nil
+}
+
+func fetchBytes(url string) ([]byte, error) {
+	client := resty.New()
+	resp, err := client.R().Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode(), url)
+	}
+
+	return resp.Body(), nil
+}
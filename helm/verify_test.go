@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// helloSha256 is the sha256 of "hello\n".
+const helloSha256 = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+
+func TestVerifyArchive(t *testing.T) {
+	c.Logger = logrus.New()
+	c.VerifySignature = false
+	path := filepath.Join(t.TempDir(), "helm.tar.gz")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{"digest and name", http.StatusOK, helloSha256 + "  helm-v3.15.2-linux-amd64.tar.gz\n", false},
+		{"bare digest", http.StatusOK, helloSha256 + "\n", false},
+		{"upper case digest", http.StatusOK, "5891B5B522D5DF086D0FF0B110FBD9D21BB4FC7163AF34D08286A2E846F6BE03  helm-v3.15.2-linux-amd64.tar.gz\n", false},
+		{"empty sha256sum", http.StatusOK, "", true},
+		{"mismatch", http.StatusOK, "0000000000000000000000000000000000000000000000000000000000000000  helm-v3.15.2-linux-amd64.tar.gz\n", true},
+		{"missing sha256sum", http.StatusNotFound, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/helm-v3.15.2-linux-amd64.tar.gz.sha256sum" {
+					http.NotFound(w, r)
+					return
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := verifyArchive(server.URL+"/helm-v3.15.2-linux-amd64.tar.gz", path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
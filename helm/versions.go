@@ -1,117 +1,164 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/go-resty/resty/v2"
 )
 
-func listVersions(verMatch string, all bool) {
+// listOptions are the flags of the versions command.
+type listOptions struct {
+	VerMatch      string
+	All           bool
+	Prerelease    bool
+	IncludeDrafts bool
+	Output        string
+	Installed     bool
+	GroupBy       string
+	Limit         int
+	Since         string
+	Reverse       bool
+}
 
-	releaseTags, err := getGitHubReleases("helm", "helm")
-	if err != nil {
-		c.Logger.WithError(err).Error("failed to read helm releases")
-	}
+func listVersions(opts listOptions) error {
 
-	if all {
-		for _, v := range releaseTags {
-			if len(verMatch) > 0 {
-				if strings.HasPrefix(v, verMatch) {
-					fmt.Printf("%s\n", v)
-				}
-			} else {
-				fmt.Printf("%s\n", v)
-			}
+	var releases GithubReleaseList
+	var releaseTags []string
+	var err error
+	if opts.Installed {
+		releaseTags, err = installedVersions()
+		if err != nil {
+			return err
 		}
 	} else {
-		justMinors(&releaseTags, verMatch)
+		releases, err = getGitHubReleases("helm", "helm")
+		if err != nil {
+			return fmt.Errorf("%w: failed to read helm releases: %w", ErrReleasesUnavailable, err)
+		}
+		releaseTags = releaseTagNames(releases, opts.Prerelease, opts.IncludeDrafts)
 	}
-}
-
-func justMinors(releaseTags *[]string, verMatch string) {
 
-	minorRelease := map[string]semver.Version{}
-	for _, v := range *releaseTags {
-		nv, err := semver.NewVersion(v)
+	if isVersionConstraint(opts.VerMatch) {
+		releaseTags, err = matchVersions(opts.VerMatch, releaseTags)
 		if err != nil {
-			c.Logger.Error(fmt.Sprintf("Error parsing SemVer for %s", v))
+			return err
 		}
-		verKey := fmt.Sprintf("%d.%d", nv.Major(), nv.Minor())
-		processVer := true
-		if len(verMatch) > 0 {
-			if !strings.HasPrefix(v, verMatch) {
-				processVer = false
-			}
-		}
-		if processVer {
-			if _, ok := minorRelease[verKey]; ok {
-				if minorRelease[verKey].Patch() < nv.Patch() {
-					minorRelease[verKey] = *nv
-				}
-			} else {
-				// add
-				minorRelease[verKey] = *nv
-			}
+		opts.VerMatch = ""
+	}
+
+	published := map[string]string{}
+	for _, release := range releases {
+		if !release.PublishedAt.IsZero() {
+			published[release.TagName] = release.PublishedAt.Format(time.RFC3339)
 		}
 	}
-	vs := []*semver.Version{}
-	for _, v := range minorRelease {
-		vs = append(vs, &v)
+
+	selected, err := groupVersions(releaseTags, published, opts)
+	if err != nil {
+		return err
 	}
+	return printVersions(newVersionEntries(selected, published), opts.Output)
+}
 
-	sort.Sort(semver.Collection(vs))
+// isPrerelease reports whether TAG has a semver prerelease component, such as -rc.1 or -alpha.2.
+func isPrerelease(tag string) bool {
+	nv, err := semver.NewVersion(tag)
+	return err == nil && len(nv.Prerelease()) > 0
+}
 
-	// helm/helm uses a "v", so we should indicate so
-	for _, v := range vs {
-		fmt.Printf("v%s\n", v)
+// groupVersions filters RELEASETAGS by the prefix and --since date of OPTS and keeps the newest
+// version of each major or minor. The newest --limit versions are returned oldest first, or newest
+// first with --reverse. Tags that are not semver are skipped.
+func groupVersions(releaseTags []string, published map[string]string, opts listOptions) ([]string, error) {
+	groupBy := opts.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = "minor"
+		if opts.All {
+			groupBy = "none"
+		}
+	}
+	if groupBy != "major" && groupBy != "minor" && groupBy != "none" {
+		return nil, fmt.Errorf("unknown --group-by %q, use major, minor or none", groupBy)
 	}
-}
 
-// GetGitHubReleases fetches all releases for the given owner and repo from GitHub.
-// curl -L \
-// -H "Accept: application/vnd.github+json" \
-// -H "X-GitHub-Api-Version: 2022-11-28" \
-// https://api.github.com/repos/kubernetes/kubernetes/releases
-func getGitHubReleases(owner, repo string) ([]string, error) {
-	client := resty.New()
-	var allReleases GithubReleaseList
-	page := 1
-	for {
-		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?page=%d", owner, repo, page)
-		resp, err := client.R().
-			SetHeader("Content-Type", "application/json").
-			SetHeader("X-GitHub-Api-Version", "2022-11-28").
-			SetHeader("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("GITHUB_TOKEN"))).
-			Get(url)
+	var since time.Time
+	if len(opts.Since) > 0 {
+		var err error
+		since, err = parseSince(opts.Since)
 		if err != nil {
 			return nil, err
 		}
+		if len(published) == 0 {
+			return nil, fmt.Errorf("--since needs release dates, which are not known for these versions")
+		}
+	}
 
-		if resp.StatusCode() != 200 {
-			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+	groups := map[string]*semver.Version{}
+	for _, v := range releaseTags {
+		if len(opts.VerMatch) > 0 && !strings.HasPrefix(v, opts.VerMatch) {
+			continue
+		}
+		nv, err := semver.NewVersion(v)
+		if err != nil {
+			c.Logger.Debugf("skipping %s, not a semver version: %s", v, err)
+			continue
+		}
+		if !since.IsZero() {
+			publishedAt, err := time.Parse(time.RFC3339, published[v])
+			if err != nil || publishedAt.Before(since) {
+				continue
+			}
 		}
 
-		var releases GithubReleaseList
-		if err := json.Unmarshal(resp.Body(), &releases); err != nil {
-			return nil, err
+		verKey := v
+		switch groupBy {
+		case "major":
+			verKey = fmt.Sprintf("%d", nv.Major())
+		case "minor":
+			verKey = fmt.Sprintf("%d.%d", nv.Major(), nv.Minor())
 		}
+		if current, ok := groups[verKey]; !ok || nv.GreaterThan(current) {
+			groups[verKey] = nv
+		}
+	}
 
-		if len(releases) == 0 {
-			break
+	// newest first, equal versions with different tags are ordered by tag so the output is stable
+	vs := []*semver.Version{}
+	for _, v := range groups {
+		vs = append(vs, v)
+	}
+	sort.Slice(vs, func(i, j int) bool {
+		if vs[i].Equal(vs[j]) {
+			return vs[i].Original() > vs[j].Original()
 		}
+		return vs[i].GreaterThan(vs[j])
+	})
+	if opts.Limit > 0 && len(vs) > opts.Limit {
+		vs = vs[:opts.Limit]
+	}
+	if !opts.Reverse {
+		slices.Reverse(vs)
+	}
 
-		allReleases = append(allReleases, releases...)
-		page++
+	tags := []string{}
+	for _, v := range vs {
+		tags = append(tags, v.Original())
 	}
+	return tags, nil
+}
 
-	var tagNames []string
-	for _, release := range allReleases {
-		tagNames = append(tagNames, release.TagName)
+// parseSince reads the --since date, either a day such as 2024-01-31 or an RFC 3339 time.
+func parseSince(since string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, since); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q, use a date such as 2024-01-31", since)
 	}
-	return tagNames, nil
+	return t, nil
 }
@@ -11,23 +11,43 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of json2yaml", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	path := filepath.Join(binPath, fmt.Sprintf("json2yaml/%s/json2yaml", ver))
 	if !fileExists(path) {
+		if err := validateVersion(ver); err != nil {
+			return err
+		}
+
 		c.Logger.Infof("Downloading version %s of json2yaml to path %s", ver, path)
-		err := downloadArtifact(ver, path)
+		err := stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(ver, stagedPath)
+		})
 		if err != nil {
-			c.Logger.WithError(err).Error("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of json2yaml", ver)
 	err := changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -39,7 +59,7 @@ func fileExists(path string) bool {
 func downloadArtifact(semver, path string) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -48,27 +68,31 @@ func downloadArtifact(semver, path string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	if targetOS == "darwin" && targetArch == "arm" {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	// https://github.com/bronze1man/json2yaml/releases/download/v1.3/json2yaml_darwin_amd64
 	actualSemver, _ := strings.CutPrefix(semver, "v")
-	url := fmt.Sprintf("https://github.com/bronze1man/json2yaml/releases/download/%s/json2yaml_%s_%s", actualSemver, targetOS, targetArch)
+	url := fmt.Sprintf("%s/bronze1man/json2yaml/releases/download/%s/json2yaml_%s_%s", githubServerURL(), actualSemver, targetOS, targetArch)
 
 	c.Logger.Tracef("downloading json2yaml from %s to %s", url, path)
 
 	client := resty.New()
-	resp, err := client.R().SetOutput(path).Get(url)
+	resp, err := githubAssetRequest(client, url).SetOutput(path).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading json2yaml: %v", err)
+		return fmt.Errorf("%w: error downloading json2yaml: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: json2yaml %s", ErrVersionNotFound, semver)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading json2yaml: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading json2yaml: status code %d", ErrDownloadFailed, resp.StatusCode())
 	}
 
 	return nil
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const (
+	latestVersion       = "latest"
+	latestStableVersion = "latest-stable"
+)
+
+// isVersionConstraint reports whether EXPR is a constraint such as ~1.7, ^3.14, >=1.28 <1.30, latest
+// or latest-stable, rather than a literal version or prefix.
+func isVersionConstraint(expr string) bool {
+	expr = strings.TrimSpace(expr)
+	if expr == latestVersion || expr == latestStableVersion {
+		return true
+	}
+	return strings.ContainsAny(expr, "~^<>=!*|, ") || strings.HasSuffix(expr, ".x") || strings.HasSuffix(expr, ".X")
+}
+
+// matchVersions returns the TAGS matching the constraint EXPR, newest first. latest matches only the
+// newest tag and latest-stable the newest tag without a prerelease component. Tags that are not
+// semver never match.
+func matchVersions(expr string, tags []string) ([]string, error) {
+	expr = strings.TrimSpace(expr)
+
+	var constraint *semver.Constraints
+	if expr != latestVersion && expr != latestStableVersion {
+		var err error
+		constraint, err = semver.NewConstraint(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", expr, err)
+		}
+	}
+
+	type match struct {
+		tag    string
+		semver *semver.Version
+	}
+	matches := []match{}
+	for _, tag := range tags {
+		nv, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		switch {
+		case expr == latestStableVersion && len(nv.Prerelease()) > 0:
+			continue
+		case constraint != nil && !constraint.Check(nv):
+			continue
+		}
+		matches = append(matches, match{tag: tag, semver: nv})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].semver.GreaterThan(matches[j].semver)
+	})
+	if constraint == nil && len(matches) > 1 {
+		matches = matches[:1]
+	}
+
+	matched := make([]string, 0, len(matches))
+	for _, m := range matches {
+		matched = append(matched, m.tag)
+	}
+	return matched, nil
+}
+
+// newestMatch returns the newest of TAGS matching the constraint EXPR.
+func newestMatch(expr string, tags []string) (string, error) {
+	matched, err := matchVersions(expr, tags)
+	if err != nil {
+		return "", err
+	}
+	if len(matched) == 0 {
+		return "", fmt.Errorf("%w: no json2yaml release matches %s", ErrVersionNotFound, expr)
+	}
+	return matched[0], nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fatih/color"
+)
+
+// toolBinaries are the binaries every installed json2yaml version should have.
+var toolBinaries = []string{"json2yaml"}
+
+// installedEntry describes one version directory under BinDir/json2yaml.
+type installedEntry struct {
+	Version     string
+	Size        int64
+	InstalledAt time.Time
+	Binaries    []string
+	Active      bool
+	Staging     bool
+	Problems    []string
+}
+
+// listInstalled prints every version directory under BinDir/json2yaml with its size, install time and
+// binaries, marks the active one and flags broken installs. Staging directories are listed too, one
+// older than stagingMaxAge is a download that was interrupted.
+func listInstalled() error {
+	toolDir := filepath.Join(c.BinDir, "json2yaml")
+	dirEntries, err := os.ReadDir(toolDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read installed versions: %w", err)
+	}
+
+	active := activeVersion()
+	entries := []installedEntry{}
+	for _, dirEntry := range dirEntries {
+		staging := strings.HasPrefix(dirEntry.Name(), stagingPrefix)
+		if !dirEntry.IsDir() || (strings.HasPrefix(dirEntry.Name(), ".") && !staging) {
+			continue
+		}
+		entry, err := inspectInstall(filepath.Join(toolDir, dirEntry.Name()))
+		if err != nil {
+			return err
+		}
+		entry.Staging = staging
+		entry.Active = entry.Version == active
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		vi, ierr := semver.NewVersion(entries[i].Version)
+		vj, jerr := semver.NewVersion(entries[j].Version)
+		if ierr != nil || jerr != nil {
+			return ierr == nil || (jerr != nil && entries[i].Version < entries[j].Version)
+		}
+		return vi.LessThan(vj)
+	})
+
+	if len(entries) == 0 {
+		fmt.Printf("no json2yaml versions installed in %s\n", toolDir)
+		return nil
+	}
+
+	activeMark := color.New(color.FgGreen, color.Bold).SprintFunc()
+	broken := color.New(color.FgRed).SprintFunc()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tVERSION\tSIZE\tINSTALLED\tBINARIES\tSTATUS")
+	for _, e := range entries {
+		mark := ""
+		if e.Active {
+			mark = activeMark("->")
+		}
+		binaries := strings.Join(e.Binaries, ",")
+		if len(binaries) == 0 {
+			binaries = "-"
+		}
+		status := "ok"
+		switch {
+		case e.Staging && time.Since(e.InstalledAt) < stagingMaxAge:
+			status = "download in progress"
+		case e.Staging:
+			status = broken("interrupted download: " + strings.Join(e.Problems, ", "))
+		case len(e.Problems) > 0:
+			status = broken("broken: " + strings.Join(e.Problems, ", "))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", mark, e.Version, humanSize(e.Size), e.InstalledAt.Format("2006-01-02 15:04"), binaries, status)
+	}
+	return w.Flush()
+}
+
+// inspectInstall reads the version directory DIR: its total size, when it was installed, which of
+// toolBinaries it has and what is wrong with it.
+func inspectInstall(dir string) (installedEntry, error) {
+	entry := installedEntry{Version: filepath.Base(dir)}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return entry, err
+	}
+	entry.InstalledAt = info.ModTime()
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".tar.gz") || strings.HasSuffix(d.Name(), ".zip") {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("leftover %s", d.Name()))
+		}
+		fileInfo, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entry.Size += fileInfo.Size()
+		return nil
+	})
+	if err != nil {
+		return entry, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	missing := []string{}
+	for _, binary := range toolBinaries {
+		if fileExists(filepath.Join(dir, binary)) {
+			entry.Binaries = append(entry.Binaries, binary)
+		} else {
+			missing = append(missing, binary)
+		}
+	}
+	if len(entry.Binaries) == 0 {
+		entry.Problems = append(entry.Problems, "no binary")
+	} else if len(missing) > 0 {
+		entry.Problems = append(entry.Problems, fmt.Sprintf("missing %s", strings.Join(missing, ",")))
+	}
+
+	return entry, nil
+}
+
+// humanSize formats SIZE in bytes with a binary unit, as in 48.2 MiB.
+func humanSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/fatih/color"
 	glog "github.com/maahsome/golang-logger"
@@ -16,6 +18,8 @@ var (
 type Config struct {
 	SymLinkDir string
 	BinDir     string
+	Refresh    bool
+	CacheTTL   time.Duration
 	Logger     *logrus.Logger
 }
 
@@ -45,12 +49,32 @@ var MainCmd = &cobra.Command{
 }
 
 func InitMainCmd(sym string, bin string, loglevel string) {
-	activateCmd.Flags().StringP("version", "v", "", "Specify the version")
-	versionsCmd.Flags().StringP("version", "v", "", "Prefix for versions to return")
+	activateCmd.Flags().StringP("version", "v", "", "Version or constraint to activate, as in 1.29.3, ~1.29, >=1.28 <1.30, latest or latest-stable")
+	versionsCmd.Flags().StringP("version", "v", "", "Prefix or constraint for versions to return, as in 1.29, ^1.29, >=1.28 <1.30, latest or latest-stable")
 	versionsCmd.Flags().BoolP("all", "a", false, "Return ALL versions")
+	versionsCmd.Flags().String("group-by", "", "Keep the newest version per major or minor, or none for every version (default minor, none with -a)")
+	versionsCmd.Flags().Int("limit", 0, "Only list the newest N versions")
+	versionsCmd.Flags().String("since", "", "Only list versions published on or after this date, as in 2024-01-31")
+	versionsCmd.Flags().Bool("reverse", false, "List the newest version first")
+	versionsCmd.Flags().StringP("output", "o", "text", "Output format: text, json, yaml or table")
+	versionsCmd.Flags().Bool("installed", false, "Only list versions installed locally, without fetching releases")
+	versionsCmd.Flags().Bool("no-color", false, "Do not colour the installed and active markers")
+	versionsCmd.Flags().Bool("prerelease", false, "Include prereleases (alpha, beta, rc)")
+	versionsCmd.Flags().Bool("include-drafts", false, "Include draft releases")
+	notesCmd.Flags().StringP("version", "v", "", "Show the notes of this release")
+	notesCmd.Flags().String("from", "", "Show the notes of the releases after this version (default the active version)")
+	notesCmd.Flags().String("to", "", "Show the notes up to and including this version (default the newest stable release)")
+
+	MainCmd.PersistentFlags().BoolVar(&c.Refresh, "refresh", false, "Ignore the cached version index and fetch it again")
+	MainCmd.PersistentFlags().DurationVar(&c.CacheTTL, "cache-ttl", defaultCacheTTL, "How long a fetched version index is reused")
 
 	MainCmd.AddCommand(activateCmd)
 	MainCmd.AddCommand(versionsCmd)
+	MainCmd.AddCommand(notesCmd)
+	MainCmd.AddCommand(outdatedCmd)
+	MainCmd.AddCommand(installedCmd)
+	MainCmd.AddCommand(currentCmd)
+	MainCmd.AddCommand(whichCmd)
 
 	c.SymLinkDir = sym
 	c.BinDir = bin
@@ -59,24 +83,107 @@ func InitMainCmd(sym string, bin string, loglevel string) {
 }
 
 var versionsCmd = &cobra.Command{
-	Use:   "versions",
-	Short: "json2yaml list versions",
-	Long:  fmt.Sprintf("%s\n\n%s", longDescription(), `Unless "-a" is specified, only the highest PATCH for each MAJOR.MINOR will be returned`),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "versions",
+	Short:        "json2yaml list versions",
+	Long:         fmt.Sprintf("%s\n\n%s", longDescription(), `Unless "-a" is specified, only the highest PATCH for each MAJOR.MINOR will be returned`),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		c.Logger.Info("Fetching a list of versions...")
 		verMatch, _ := cmd.Flags().GetString("version")
 		returnAll, _ := cmd.Flags().GetBool("all")
-		listVersions(verMatch, returnAll)
+		prerelease, _ := cmd.Flags().GetBool("prerelease")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		limit, _ := cmd.Flags().GetInt("limit")
+		since, _ := cmd.Flags().GetString("since")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		output, _ := cmd.Flags().GetString("output")
+		installed, _ := cmd.Flags().GetBool("installed")
+		if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+			color.NoColor = true
+		}
+		includeDrafts, _ := cmd.Flags().GetBool("include-drafts")
+		return listVersions(listOptions{
+			VerMatch:      verMatch,
+			All:           returnAll,
+			Prerelease:    prerelease,
+			IncludeDrafts: includeDrafts,
+			Output:        output,
+			Installed:     installed,
+			GroupBy:       groupBy,
+			Limit:         limit,
+			Since:         since,
+			Reverse:       reverse,
+		})
 	},
 }
 
 var activateCmd = &cobra.Command{
-	Use:   "activate",
-	Short: "json2yaml activate",
-	Long:  longDescription(),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:          "activate",
+	Short:        "json2yaml activate",
+	Long:         longDescription(),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 
 		activateVer, _ := cmd.Flags().GetString("version")
-		activateVersion(activateVer, c.BinDir, c.SymLinkDir)
+		return activateVersion(activateVer, c.BinDir, c.SymLinkDir)
+	},
+}
+
+var notesCmd = &cobra.Command{
+	Use:          "notes",
+	Short:        "json2yaml release notes",
+	Long:         longDescription(),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ver, _ := cmd.Flags().GetString("version")
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		return showNotes(ver, from, to)
+	},
+}
+
+var outdatedCmd = &cobra.Command{
+	Use:          "outdated",
+	Short:        "json2yaml check for newer releases",
+	Long:         fmt.Sprintf("%s\n\nExits with code %d when a newer release exists", longDescription(), outdatedExitCode),
+	SilenceUsage: true,
+	// a newer release is reported through the exit code, not as a failure, other errors are logged here
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := showOutdated()
+		if err != nil && !errors.Is(err, ErrOutdated) {
+			c.Logger.Error(err)
+		}
+		return err
+	},
+}
+
+var installedCmd = &cobra.Command{
+	Use:          "installed",
+	Short:        "json2yaml list installed versions",
+	Long:         longDescription(),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listInstalled()
+	},
+}
+
+var currentCmd = &cobra.Command{
+	Use:          "current",
+	Short:        "json2yaml print the active version",
+	Long:         longDescription(),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showCurrent()
+	},
+}
+
+var whichCmd = &cobra.Command{
+	Use:          "which",
+	Short:        "json2yaml print the path of the active binary",
+	Long:         longDescription(),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showWhich()
 	},
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fatih/color"
+)
+
+// outdatedExitCode is the exit code the host should use when a newer release exists, so login hooks
+// can tell it apart from a failure.
+const outdatedExitCode = 10
+
+// showOutdated compares the active json2yaml version with the newest stable releases in the same
+// minor, in the same major and overall. When any of them is newer it returns an *ExitError wrapping
+// ErrOutdated, with a Code of outdatedExitCode.
+func showOutdated() error {
+	active := activeVersion()
+	if len(active) == 0 {
+		return fmt.Errorf("no json2yaml version is active")
+	}
+	activeVer, err := semver.NewVersion(active)
+	if err != nil {
+		return fmt.Errorf("the active json2yaml version %s is not a semver version", active)
+	}
+
+	available, err := stableVersions()
+	if err != nil {
+		return err
+	}
+
+	var latestPatch, latestMinor, latest *semver.Version
+	for _, v := range available {
+		nv, err := semver.NewVersion(v)
+		if err != nil {
+			c.Logger.Debugf("skipping %s, not a semver version: %s", v, err)
+			continue
+		}
+		if latest == nil || nv.GreaterThan(latest) {
+			latest = nv
+		}
+		if nv.Major() != activeVer.Major() {
+			continue
+		}
+		if latestMinor == nil || nv.GreaterThan(latestMinor) {
+			latestMinor = nv
+		}
+		if nv.Minor() == activeVer.Minor() && (latestPatch == nil || nv.GreaterThan(latestPatch)) {
+			latestPatch = nv
+		}
+	}
+
+	newer := color.New(color.FgYellow, color.Bold).SprintFunc()
+	outdated := false
+	report := func(label string, v *semver.Version) {
+		switch {
+		case v == nil:
+			fmt.Printf("  %-14s -\n", label)
+		case v.GreaterThan(activeVer):
+			outdated = true
+			fmt.Printf("  %-14s %s\n", label, newer(v.Original()))
+		default:
+			fmt.Printf("  %-14s %s (up to date)\n", label, v.Original())
+		}
+	}
+
+	fmt.Printf("json2yaml %s is active\n", active)
+	report("latest patch", latestPatch)
+	report("latest minor", latestMinor)
+	report("latest", latest)
+
+	if outdated {
+		return &ExitError{
+			Err:  fmt.Errorf("%w: json2yaml %s, newest is %s", ErrOutdated, active, latest.Original()),
+			Code: outdatedExitCode,
+		}
+	}
+	return nil
+}
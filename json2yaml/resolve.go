@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// normalizeVersion returns VER the way json2yaml versions are activated, with a "v" prefix, as in v1.3.
+func normalizeVersion(ver string) string {
+	ver = strings.TrimSpace(ver)
+	if len(ver) == 0 {
+		return ver
+	}
+	return "v" + strings.TrimPrefix(ver, "v")
+}
+
+// validateVersion checks that VER is published before anything is downloaded, when it is not the
+// error lists the closest published versions. A version missing from the cached releases is looked
+// up again, it may have been published since they were fetched. When GitHub is rate limited the
+// check is skipped and a missing version is caught by the download instead.
+func validateVersion(ver string) error {
+	releases, err := getGitHubReleases("bronze1man", "json2yaml")
+	if err == nil && !c.Refresh && !hasVersion(releaseTagNames(releases, true, true), ver) {
+		c.Logger.Debugf("json2yaml %s is not in the cached releases, fetching them again", ver)
+		refreshed, rerr := refreshGitHubReleases("bronze1man", "json2yaml")
+		switch {
+		case rerr == nil:
+			releases = refreshed
+		case errors.Is(rerr, ErrRateLimited):
+			err = rerr
+		default:
+			c.Logger.WithError(rerr).Warn("failed to fetch the json2yaml releases again")
+		}
+	}
+	if errors.Is(err, ErrRateLimited) {
+		c.Logger.WithError(err).Warnf("not checking json2yaml %s against the github releases", ver)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to read json2yaml releases: %w", ErrReleasesUnavailable, err)
+	}
+	// an exact tag was asked for, so prereleases and drafts count
+	releaseTags := releaseTagNames(releases, true, true)
+
+	if hasVersion(releaseTags, ver) {
+		return nil
+	}
+	return versionNotFound(ver, releaseTags)
+}
+
+// hasVersion reports whether VER is one of TAGS once both are normalized.
+func hasVersion(tags []string, ver string) bool {
+	for _, v := range tags {
+		if normalizeVersion(v) == ver {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveConstraint returns the newest published json2yaml version matching the constraint EXPR.
+func resolveConstraint(expr string) (string, error) {
+	releases, err := getGitHubReleases("bronze1man", "json2yaml")
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read json2yaml releases: %w", ErrReleasesUnavailable, err)
+	}
+
+	return newestMatch(expr, releaseTagNames(releases, true, false))
+}
+
+// stableVersions returns the published json2yaml versions, leaving out prereleases and drafts.
+func stableVersions() ([]string, error) {
+	releases, err := getGitHubReleases("bronze1man", "json2yaml")
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read json2yaml releases: %w", ErrReleasesUnavailable, err)
+	}
+
+	return releaseTagNames(releases, false, false), nil
+}
+
+// versionNotFound builds the ErrVersionNotFound error for VER, with suggestions from AVAILABLE.
+func versionNotFound(ver string, available []string) error {
+	suggestions := closestVersions(ver, available, 5)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("%w: json2yaml %s", ErrVersionNotFound, ver)
+	}
+	return fmt.Errorf("%w: json2yaml %s, closest versions: %s", ErrVersionNotFound, ver, strings.Join(suggestions, ", "))
+}
+
+// closestVersions returns up to COUNT of the AVAILABLE versions nearest to VER. Versions are ranked
+// by how far apart their MAJOR, MINOR and PATCH are, or by common prefix when VER is not semver.
+func closestVersions(ver string, available []string, count int) []string {
+	type candidate struct {
+		version  string
+		semver   *semver.Version
+		distance uint64
+	}
+
+	target, terr := semver.NewVersion(ver)
+	candidates := []candidate{}
+	for _, v := range available {
+		nv := normalizeVersion(v)
+		if terr != nil {
+			prefix := commonPrefixLen(nv, ver)
+			if prefix > 0 {
+				candidates = append(candidates, candidate{version: nv, distance: uint64(len(ver) - prefix)})
+			}
+			continue
+		}
+		sv, err := semver.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			version: nv,
+			semver:  sv,
+			distance: absDiff(sv.Major(), target.Major())<<40 |
+				absDiff(sv.Minor(), target.Minor())<<20 |
+				absDiff(sv.Patch(), target.Patch()),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		if candidates[i].semver != nil && candidates[j].semver != nil {
+			return candidates[i].semver.GreaterThan(candidates[j].semver)
+		}
+		return candidates[i].version > candidates[j].version
+	})
+
+	suggestions := []string{}
+	seen := map[string]bool{}
+	for _, cand := range candidates {
+		if len(suggestions) == count {
+			break
+		}
+		if !seen[cand.version] {
+			seen[cand.version] = true
+			suggestions = append(suggestions, cand.version)
+		}
+	}
+	return suggestions
+}
+
+func absDiff(a, b uint64) uint64 {
+	diff := a - b
+	if b > a {
+		diff = b - a
+	}
+	// keep each component inside its 20 bits of the distance
+	if diff > 0xfffff {
+		diff = 0xfffff
+	}
+	return diff
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -10,23 +10,43 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of jsonui", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	path := filepath.Join(binPath, fmt.Sprintf("jsonui/%s/jsonui", ver))
 	if !fileExists(path) {
+		if err := validateVersion(ver); err != nil {
+			return err
+		}
+
 		c.Logger.Infof("Downloading version %s of jsonui to path %s", ver, path)
-		err := downloadArtifact(ver, path)
+		err := stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(ver, stagedPath)
+		})
 		if err != nil {
-			c.Logger.WithError(err).Error("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of jsonui", ver)
 	err := changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -38,7 +58,7 @@ func fileExists(path string) bool {
 func downloadArtifact(semver, path string) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -47,23 +67,27 @@ func downloadArtifact(semver, path string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	if targetOS == "darwin" && targetArch == "arm" {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
-	url := fmt.Sprintf("https://github.com/gulyasm/jsonui/releases/download/%s/jsonui_%s_%s", semver, targetOS, targetArch)
+	url := fmt.Sprintf("%s/gulyasm/jsonui/releases/download/%s/jsonui_%s_%s", githubServerURL(), semver, targetOS, targetArch)
 
 	client := resty.New()
-	resp, err := client.R().SetOutput(path).Get(url)
+	resp, err := githubAssetRequest(client, url).SetOutput(path).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading jsonui: %v", err)
+		return fmt.Errorf("%w: error downloading jsonui: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: jsonui %s", ErrVersionNotFound, semver)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading jsonui: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading jsonui: status code %d", ErrDownloadFailed, resp.StatusCode())
 	}
 
 	return nil
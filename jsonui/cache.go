@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultCacheTTL is how long a fetched version index is used before it is fetched again.
+const defaultCacheTTL = time.Hour
+
+// cacheEntry is a version index as stored under BinDir/jsonui/.cache.
+type cacheEntry[T any] struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Data      T         `json:"data"`
+}
+
+func cacheDir() string {
+	return filepath.Join(c.BinDir, "jsonui", ".cache")
+}
+
+// cachedFetch returns the cached index NAME while it is younger than --cache-ttl, otherwise it calls
+// FETCH and stores the result. --refresh skips the cached copy, and when FETCH fails any cached copy
+// is returned regardless of its age so listing still works offline.
+func cachedFetch[T any](name string, fetch func() (T, error)) (T, error) {
+	path := filepath.Join(cacheDir(), name+".json")
+	cached, cacheErr := readCache[T](path)
+
+	if cacheErr == nil && !c.Refresh && time.Since(cached.FetchedAt) < c.CacheTTL {
+		c.Logger.Debugf("using cached %s from %s", name, cached.FetchedAt.Format(time.RFC3339))
+		return cached.Data, nil
+	}
+
+	data, err := refetch(name, fetch)
+	if err != nil {
+		if cacheErr == nil {
+			c.Logger.WithError(err).Warnf("failed to fetch %s, using the cached copy from %s", name, cached.FetchedAt.Format(time.RFC3339))
+			return cached.Data, nil
+		}
+		return data, err
+	}
+
+	return data, nil
+}
+
+// refetch calls FETCH for the index NAME and stores the result, ignoring any cached copy. Unlike
+// cachedFetch it never falls back to the cache, a version missing from a cached index is looked up
+// again this way before it is reported as not found.
+func refetch[T any](name string, fetch func() (T, error)) (T, error) {
+	data, err := fetch()
+	if err != nil {
+		return data, err
+	}
+
+	path := filepath.Join(cacheDir(), name+".json")
+	if werr := writeCache(path, cacheEntry[T]{FetchedAt: time.Now(), Data: data}); werr != nil {
+		c.Logger.WithError(werr).Warnf("failed to write cache %s", path)
+	}
+
+	return data, nil
+}
+
+func readCache[T any](path string) (cacheEntry[T], error) {
+	var entry cacheEntry[T]
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal(raw, &entry)
+	return entry, err
+}
+
+// writeCache writes through a temporary file so a concurrent reader never sees a partial index.
+func writeCache(path string, entry any) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
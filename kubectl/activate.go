@@ -10,23 +10,54 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	// a release marker resolves to a published version on dl.k8s.io, without the GitHub API
+	fromMarker := isReleaseMarker(ver)
+	if fromMarker {
+		resolved, err := resolveReleaseMarker(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of kubectl", ver, resolved)
+		ver = resolved
+	} else if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of kubectl", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	path := filepath.Join(binPath, fmt.Sprintf("kubectl/%s/kubectl", ver))
 	if !fileExists(path) {
+		if !fromMarker {
+			if err := validateVersion(ver); err != nil {
+				return err
+			}
+		}
+
 		c.Logger.Infof("Downloading version %s of kubectl to path %s", ver, path)
-		err := downloadArtifact(ver, path)
+		err := stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(ver, stagedPath)
+		})
 		if err != nil {
-			c.Logger.WithError(err).Error("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of kubectl", ver)
 	err := changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -38,7 +69,7 @@ func fileExists(path string) bool {
 func downloadArtifact(semver, path string) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -47,19 +78,31 @@ func downloadArtifact(semver, path string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm64"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
-	url := fmt.Sprintf("https://storage.googleapis.com/kubernetes-release/release/%s/bin/%s/%s/kubectl", semver, targetOS, targetArch)
+	url := fmt.Sprintf("%s/%s/bin/%s/%s/kubectl", kubernetesReleaseURL, semver, targetOS, targetArch)
 
 	client := resty.New()
 	resp, err := client.R().SetOutput(path).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading kubectl: %v", err)
+		return fmt.Errorf("%w: error downloading kubectl: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: kubectl %s", ErrVersionNotFound, semver)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading kubectl: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading kubectl: status code %d", ErrDownloadFailed, resp.StatusCode())
+	}
+
+	// verify, and never leave a bad binary behind for the fileExists check to pick up
+	if err := verifyBinary(url, path); err != nil {
+		if rerr := os.Remove(path); rerr != nil {
+			c.Logger.WithError(rerr).Error("failed to remove unverified binary")
+		}
+		return fmt.Errorf("%w: kubectl: %w", ErrVerifyFailed, err)
 	}
 
 	return nil
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsVersionConstraint(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"latest", true},
+		{"latest-stable", true},
+		{" latest ", true},
+		{"~1.29", true},
+		{"^1.29", true},
+		{">=1.28 <1.30", true},
+		{"1.29.x", true},
+		{"1.29.*", true},
+		{"1.28 || 1.29", true},
+		{"v1.29.3", false},
+		{"1.29", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got := isVersionConstraint(tt.expr); got != tt.want {
+				t.Fatalf("isVersionConstraint(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchVersions(t *testing.T) {
+	tags := []string{"v1.28.9", "v1.29.3", "v1.30.0-rc.1", "v1.29.10", "not-semver", "v1.27.0"}
+
+	tests := []struct {
+		name    string
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		{"latest includes prereleases", "latest", []string{"v1.30.0-rc.1"}, false},
+		{"latest-stable skips prereleases", "latest-stable", []string{"v1.29.10"}, false},
+		{"tilde newest first", "~1.29", []string{"v1.29.10", "v1.29.3"}, false},
+		{"range", ">=1.28 <1.30", []string{"v1.29.10", "v1.29.3", "v1.28.9"}, false},
+		{"no match", "~1.31", []string{}, false},
+		{"invalid", ">=foo", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchVersions(tt.expr, tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matchVersions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("matchVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
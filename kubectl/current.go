@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// showCurrent prints the active kubectl version, read from the kubectl symlink in SymLinkDir. Like
+// showWhich it only looks at the local filesystem, so it is fast enough for a shell prompt.
+func showCurrent() error {
+	target, err := resolveActiveLink()
+	if err != nil {
+		return err
+	}
+
+	// a link outside BinDir has no version we know of, print nothing rather than a guess
+	ver := binDirVersion(target)
+	if len(ver) == 0 {
+		return fmt.Errorf("the active kubectl at %s is not a version installed in %s", target, filepath.Join(c.BinDir, "kubectl"))
+	}
+	fmt.Println(ver)
+	return nil
+}
+
+// showWhich prints the path of the kubectl binary the symlink in SymLinkDir resolves to.
+func showWhich() error {
+	target, err := resolveActiveLink()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(target)
+	return nil
+}
+
+// resolveActiveLink returns the target of the kubectl symlink in SymLinkDir. It warns when the target
+// is missing or outside BinDir, and when an earlier PATH entry shadows SymLinkDir.
+func resolveActiveLink() (string, error) {
+	link := filepath.Join(c.SymLinkDir, "kubectl")
+	target, err := activeLinkTarget()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("no kubectl version is active, %s does not exist", link)
+		}
+		return "", fmt.Errorf("failed to read the kubectl symlink %s: %w", link, err)
+	}
+
+	if len(binDirVersion(target)) == 0 {
+		c.Logger.Warnf("%s points to %s, outside %s", link, target, filepath.Join(c.BinDir, "kubectl"))
+	}
+	if !fileExists(target) {
+		c.Logger.Warnf("%s points to %s, which does not exist", link, target)
+	}
+	warnShadowed()
+
+	return target, nil
+}
+
+// warnShadowed warns about every PATH entry before SymLinkDir that has one of toolBinaries, since
+// the shell runs that binary instead of the active one. It also warns when SymLinkDir is not in PATH.
+func warnShadowed() {
+	symLinkDir, err := filepath.Abs(c.SymLinkDir)
+	if err != nil {
+		return
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if len(dir) == 0 {
+			continue
+		}
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			continue
+		}
+		if dir == symLinkDir {
+			return
+		}
+		for _, binary := range toolBinaries {
+			if fileExists(filepath.Join(dir, binary)) {
+				c.Logger.Warnf("%s shadows the active %s, %s comes before %s in PATH", filepath.Join(dir, binary), binary, dir, symLinkDir)
+			}
+		}
+	}
+	c.Logger.Warnf("%s is not in PATH", symLinkDir)
+}
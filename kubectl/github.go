@@ -0,0 +1,303 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultGitHubAPIURL is used unless GITHUB_API_URL points somewhere else, such as a GitHub
+	// Enterprise server at https://<host>/api/v3.
+	defaultGitHubAPIURL = "https://api.github.com"
+	// githubTokenHint is appended to rate limit errors.
+	githubTokenHint = "set GITHUB_TOKEN or GH_TOKEN, or log in with the gh CLI, to raise the limit"
+	// githubRateLimitWait is the longest we wait for the GitHub rate limit to reset before giving up.
+	githubRateLimitWait = time.Minute
+	// githubRateLimitRetries is how many times we wait for the rate limit during one listing.
+	githubRateLimitRetries = 3
+)
+
+// githubPage is a stored release listing page, replayed when GitHub answers 304 Not Modified.
+type githubPage struct {
+	ETag string          `json:"etag"`
+	Next string          `json:"next"`
+	Body json.RawMessage `json:"body"`
+}
+
+// getGitHubReleases returns the releases for the given owner and repo, from the on-disk cache
+// while it is fresh.
+func getGitHubReleases(owner, repo string) (GithubReleaseList, error) {
+	return cachedFetch(fmt.Sprintf("%s_%s_releases", owner, repo), func() (GithubReleaseList, error) {
+		return fetchGitHubReleases(owner, repo)
+	})
+}
+
+// refreshGitHubReleases fetches the releases for the given owner and repo again, ignoring the cached
+// copy, and updates the cache.
+func refreshGitHubReleases(owner, repo string) (GithubReleaseList, error) {
+	return refetch(fmt.Sprintf("%s_%s_releases", owner, repo), func() (GithubReleaseList, error) {
+		return fetchGitHubReleases(owner, repo)
+	})
+}
+
+// releaseTagNames returns the tags of RELEASES, leaving out drafts and prereleases unless asked for.
+// A tag with a semver prerelease component counts as a prerelease even when GitHub does not flag it.
+func releaseTagNames(releases GithubReleaseList, prerelease bool, drafts bool) []string {
+	var tagNames []string
+	for _, release := range releases {
+		if release.Draft && !drafts {
+			continue
+		}
+		if (release.Prerelease || isPrerelease(release.TagName)) && !prerelease {
+			continue
+		}
+		tagNames = append(tagNames, release.TagName)
+	}
+	return tagNames
+}
+
+// FetchGitHubReleases fetches all releases for the given owner and repo from GitHub, 100 per page,
+// following the Link rel="next" header until the last page.
+// Every page is sent with the ETag it was last fetched with, an unchanged page comes back as
+// 304 Not Modified, which does not count against the rate limit, and the stored body is reused.
+// curl -L \
+// -H "Accept: application/vnd.github+json" \
+// -H "X-GitHub-Api-Version: 2022-11-28" \
+// https://api.github.com/repos/kubernetes/kubernetes/releases?per_page=100
+func fetchGitHubReleases(owner, repo string) (GithubReleaseList, error) {
+	client := resty.New()
+	token := githubToken()
+	var allReleases GithubReleaseList
+	retries := 0
+	url := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100", githubAPIURL(), owner, repo)
+	for len(url) > 0 {
+		req := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetHeader("X-GitHub-Api-Version", "2022-11-28")
+		if len(token) > 0 {
+			req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", token))
+		}
+
+		stored, storedErr := readGitHubPage(url)
+		if storedErr == nil && len(stored.ETag) > 0 {
+			req.SetHeader("If-None-Match", stored.ETag)
+		}
+
+		resp, err := req.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		c.Logger.Debugf("GitHub rate limit remaining: %s", resp.Header().Get("X-RateLimit-Remaining"))
+
+		body := resp.Body()
+		next := nextPageURL(resp.Header().Get("Link"))
+		switch resp.StatusCode() {
+		case http.StatusNotModified:
+			c.Logger.Debugf("%s not modified, using the stored page", url)
+			body = stored.Body
+			next = stored.Next
+		case http.StatusOK:
+			if etag := resp.Header().Get("ETag"); len(etag) > 0 {
+				if werr := writeGitHubPage(url, githubPage{ETag: etag, Next: next, Body: body}); werr != nil {
+					c.Logger.WithError(werr).Warnf("failed to store %s", url)
+				}
+			}
+		case http.StatusForbidden, http.StatusTooManyRequests:
+			wait, err := rateLimitWait(resp)
+			if err != nil {
+				return nil, err
+			}
+			if retries == githubRateLimitRetries {
+				return nil, fmt.Errorf("%w: still limited after %d retries, %s", ErrRateLimited, retries, githubTokenHint)
+			}
+			retries++
+			c.Logger.Warnf("GitHub rate limit reached, waiting %s for it to reset", wait)
+			time.Sleep(wait)
+			continue
+		default:
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+		}
+
+		var releases GithubReleaseList
+		if err := json.Unmarshal(body, &releases); err != nil {
+			return nil, err
+		}
+
+		allReleases = append(allReleases, releases...)
+		url = next
+	}
+
+	return allReleases, nil
+}
+
+// rateLimitWait works out how long to wait on a 403 or 429 from GitHub. It returns an error when the
+// response is not a rate limit, or when the limit resets further out than githubRateLimitWait.
+func rateLimitWait(resp *resty.Response) (time.Duration, error) {
+	// secondary rate limits send Retry-After
+	if retryAfter, err := strconv.Atoi(resp.Header().Get("Retry-After")); err == nil {
+		wait := time.Duration(retryAfter) * time.Second
+		if wait <= githubRateLimitWait {
+			return wait, nil
+		}
+		return 0, fmt.Errorf("%w: GitHub asked to retry after %s, %s", ErrRateLimited, wait, githubTokenHint)
+	}
+
+	if resp.Header().Get("X-RateLimit-Remaining") != "0" {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+	}
+
+	resetUnix, err := strconv.ParseInt(resp.Header().Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: GitHub API rate limit reached, %s", ErrRateLimited, githubTokenHint)
+	}
+
+	reset := time.Unix(resetUnix, 0)
+	wait := time.Until(reset) + time.Second
+	if wait > githubRateLimitWait {
+		return 0, fmt.Errorf("%w: GitHub API rate limit reached, it resets at %s (in %s), %s",
+			ErrRateLimited, reset.Format(time.Kitchen), time.Until(reset).Round(time.Second), githubTokenHint)
+	}
+	if wait < 0 {
+		wait = time.Second
+	}
+	return wait, nil
+}
+
+// nextPageURL returns the rel="next" target of a GitHub Link header, or "" on the last page.
+// <https://api.github.com/repositories/1/releases?per_page=100&page=2>; rel="next", <...>; rel="last"
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// githubAPIURL returns the GitHub API base URL, GITHUB_API_URL when set.
+func githubAPIURL() string {
+	if apiURL := os.Getenv("GITHUB_API_URL"); len(apiURL) > 0 {
+		return strings.TrimSuffix(apiURL, "/")
+	}
+	return defaultGitHubAPIURL
+}
+
+// githubServerURL returns the base URL release assets are downloaded from. GITHUB_SERVER_URL wins,
+// otherwise it is worked out from the API URL, https://<host>/api/v3 serves https://<host>.
+func githubServerURL() string {
+	if serverURL := os.Getenv("GITHUB_SERVER_URL"); len(serverURL) > 0 {
+		return strings.TrimSuffix(serverURL, "/")
+	}
+	apiURL := githubAPIURL()
+	if apiURL == defaultGitHubAPIURL {
+		return "https://github.com"
+	}
+	return strings.TrimSuffix(apiURL, "/api/v3")
+}
+
+// githubHost returns the host name the gh CLI would store credentials under for the API URL.
+func githubHost() string {
+	host := strings.TrimPrefix(strings.TrimPrefix(githubServerURL(), "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
+
+// githubToken returns the token to authenticate with, from GITHUB_TOKEN, GH_TOKEN, the enterprise
+// variables when talking to a GitHub Enterprise server, or the gh CLI hosts.yml. An empty token means
+// requests go out unauthenticated.
+func githubToken() string {
+	envVars := []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	if githubHost() != "github.com" {
+		envVars = append([]string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}, envVars...)
+	}
+	for _, envVar := range envVars {
+		if token := os.Getenv(envVar); len(token) > 0 {
+			c.Logger.Debugf("using GitHub token from %s", envVar)
+			return token
+		}
+	}
+
+	token, err := ghHostsToken(githubHost())
+	if err != nil {
+		c.Logger.WithError(err).Debug("no GitHub token from the gh CLI")
+		return ""
+	}
+	return token
+}
+
+// ghHostsToken reads the oauth_token for HOST from the gh CLI hosts.yml. Newer gh releases keep the
+// token in the system keyring instead, in which case there is nothing to find here.
+//
+//	github.com:
+//	    user: someone
+//	    oauth_token: gho_xxxx
+func ghHostsToken(host string) (string, error) {
+	configDir := os.Getenv("GH_CONFIG_DIR")
+	if len(configDir) == 0 {
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); len(xdg) > 0 {
+			configDir = filepath.Join(xdg, "gh")
+		} else {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			configDir = filepath.Join(home, ".config", "gh")
+		}
+	}
+
+	raw, err := os.ReadFile(filepath.Join(configDir, "hosts.yml"))
+	if err != nil {
+		return "", err
+	}
+
+	hosts := map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}{}
+	if err := yaml.Unmarshal(raw, &hosts); err != nil {
+		return "", err
+	}
+
+	token := hosts[host].OAuthToken
+	if len(token) == 0 {
+		return "", fmt.Errorf("no oauth_token for %s in hosts.yml", host)
+	}
+	c.Logger.Debugf("using GitHub token for %s from the gh CLI", host)
+	return token, nil
+}
+
+// githubPagePath names the stored page after a hash of its URL, below the plugin cache directory.
+func githubPagePath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(cacheDir(), "github", hex.EncodeToString(sum[:])+".json")
+}
+
+func readGitHubPage(url string) (githubPage, error) {
+	var stored githubPage
+	raw, err := os.ReadFile(githubPagePath(url))
+	if err != nil {
+		return stored, err
+	}
+	err = json.Unmarshal(raw, &stored)
+	return stored, err
+}
+
+func writeGitHubPage(url string, page githubPage) error {
+	return writeCache(githubPagePath(url), page)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"empty", "", ""},
+		{"next and last", `<https://api.github.com/repositories/1/releases?per_page=100&page=2>; rel="next", <https://api.github.com/repositories/1/releases?per_page=100&page=5>; rel="last"`, "https://api.github.com/repositories/1/releases?per_page=100&page=2"},
+		{"next after prev", `<https://api.github.com/repositories/1/releases?page=1>; rel="prev", <https://api.github.com/repositories/1/releases?page=3>; rel="next"`, "https://api.github.com/repositories/1/releases?page=3"},
+		{"last page", `<https://api.github.com/repositories/1/releases?page=1>; rel="first", <https://api.github.com/repositories/1/releases?page=4>; rel="prev"`, ""},
+		{"no rel", `<https://api.github.com/repositories/1/releases?page=2>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextPageURL(tt.link); got != tt.want {
+				t.Fatalf("nextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	soon := strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10)
+	later := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		wantMin     time.Duration
+		wantMax     time.Duration
+		wantErr     bool
+		rateLimited bool
+	}{
+		{"retry after", map[string]string{"Retry-After": "5"}, 5 * time.Second, 5 * time.Second, false, false},
+		{"retry after wins over reset", map[string]string{"Retry-After": "5", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": later}, 5 * time.Second, 5 * time.Second, false, false},
+		{"retry after too long", map[string]string{"Retry-After": "3600"}, 0, 0, true, true},
+		{"reset soon", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": soon}, 9 * time.Second, 12 * time.Second, false, false},
+		{"reset in the past", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": past}, time.Second, time.Second, false, false},
+		{"reset too far out", map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": later}, 0, 0, true, true},
+		{"no reset header", map[string]string{"X-RateLimit-Remaining": "0"}, 0, 0, true, true},
+		{"not a rate limit", map[string]string{"X-RateLimit-Remaining": "42"}, 0, 0, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+			resp := &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusForbidden, Header: header}}
+
+			wait, err := rateLimitWait(resp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rateLimitWait() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrRateLimited) != tt.rateLimited {
+				t.Fatalf("rateLimitWait() error = %v, want ErrRateLimited %v", err, tt.rateLimited)
+			}
+			if wait < tt.wantMin || wait > tt.wantMax {
+				t.Fatalf("rateLimitWait() = %s, want between %s and %s", wait, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ require (
 	github.com/maahsome/golang-logger v0.0.1
 	github.com/sirupsen/logrus v1.9.3
 	github.com/spf13/cobra v1.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
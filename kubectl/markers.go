@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// kubernetesReleaseURL serves the kubectl binaries and the release markers.
+	kubernetesReleaseURL = "https://dl.k8s.io/release"
+	// markerMinors is how many minors, newest first, are listed from the release markers.
+	markerMinors = 10
+)
+
+var releaseMarkerRe = regexp.MustCompile(`^(stable|latest)(-\d+\.\d+)?$`)
+
+// isReleaseMarker reports whether VER names a dl.k8s.io release marker such as stable, latest,
+// stable-1.29 or latest-1.29. latest-stable is read from the stable marker.
+func isReleaseMarker(ver string) bool {
+	ver = strings.TrimSpace(ver)
+	return ver == latestStableVersion || releaseMarkerRe.MatchString(ver)
+}
+
+// resolveReleaseMarker returns the version the release marker VER points at.
+func resolveReleaseMarker(ver string) (string, error) {
+	ver = strings.TrimSpace(ver)
+	if ver == latestStableVersion {
+		ver = "stable"
+	}
+	return fetchReleaseMarker(ver)
+}
+
+// fetchReleaseMarker reads <NAME>.txt from dl.k8s.io, which holds a single version such as v1.29.3.
+func fetchReleaseMarker(name string) (string, error) {
+	url := fmt.Sprintf("%s/%s.txt", kubernetesReleaseURL, name)
+	resp, err := resty.New().R().Get(url)
+	if err != nil {
+		return "", fmt.Errorf("%w: error reading %s: %w", ErrReleasesUnavailable, url, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return "", fmt.Errorf("%w: no kubectl release marker %s", ErrVersionNotFound, name)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return "", fmt.Errorf("%w: error reading %s: status code %d", ErrReleasesUnavailable, url, resp.StatusCode())
+	}
+
+	ver := strings.TrimSpace(string(resp.Body()))
+	if _, err := semver.NewVersion(ver); err != nil {
+		return "", fmt.Errorf("%w: %s does not hold a version: %q", ErrReleasesUnavailable, url, ver)
+	}
+	return ver, nil
+}
+
+// markerVersions lists the newest patch of the last markerMinors minors from the stable-1.XX
+// markers, plus the latest marker when PRERELEASE is set. It is used when GitHub is rate limited.
+func markerVersions(prerelease bool) ([]string, error) {
+	stable, err := fetchReleaseMarker("stable")
+	if err != nil {
+		return nil, err
+	}
+	nv, err := semver.NewVersion(stable)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []string{stable}
+	for minor := int64(nv.Minor()) - 1; minor >= 0 && int64(nv.Minor())-minor < markerMinors; minor-- {
+		ver, err := fetchReleaseMarker(fmt.Sprintf("stable-%d.%d", nv.Major(), minor))
+		if err != nil {
+			c.Logger.WithError(err).Debugf("stopped listing release markers at %d.%d", nv.Major(), minor)
+			break
+		}
+		versions = append(versions, ver)
+	}
+
+	if prerelease {
+		latest, err := fetchReleaseMarker("latest")
+		if err != nil {
+			return nil, err
+		}
+		if latest != stable {
+			versions = append(versions, latest)
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClosestVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		ver       string
+		available []string
+		count     int
+		want      []string
+	}{
+		{"same minor first", "v1.29.4", []string{"v1.28.4", "v1.29.3", "v1.29.5", "v1.30.4"}, 2, []string{"v1.29.5", "v1.29.3"}},
+		{"minor beats major", "v1.29.3", []string{"v2.29.3", "v1.31.3"}, 1, []string{"v1.31.3"}},
+		{"patch distance stays below a minor", "v1.29.3", []string{"v1.30.3", "v1.29.2000000"}, 2, []string{"v1.29.2000000", "v1.30.3"}},
+		{"normalized and deduplicated", "v1.29.4", []string{"1.29.3", "v1.29.3"}, 5, []string{"v1.29.3"}},
+		{"count limits", "v1.29.4", []string{"v1.29.1", "v1.29.2", "v1.29.3"}, 1, []string{"v1.29.3"}},
+		{"prefix when not semver", "v1.2x", []string{"v1.29.3", "v2.0.0", "v1.28.0"}, 5, []string{"v1.29.3", "v1.28.0", "v2.0.0"}},
+		{"nothing close", "v1.29.4", []string{"nightly"}, 5, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closestVersions(tt.ver, tt.available, tt.count); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("closestVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// verifyBinary checks the binary at PATH against the kubectl.sha256 published next to URL.
+func verifyBinary(url string, path string) error {
+	client := resty.New()
+	resp, err := client.R().Get(fmt.Sprintf("%s.sha256", url))
+	if err != nil {
+		return fmt.Errorf("failed to fetch kubectl.sha256: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return fmt.Errorf("failed to fetch kubectl.sha256: status code %d", resp.StatusCode())
+	}
+
+	// the file holds the bare hex digest, newer releases may add the file name after it
+	fields := strings.Fields(string(resp.Body()))
+	if len(fields) == 0 {
+		return fmt.Errorf("empty kubectl.sha256 for %s", url)
+	}
+
+	return verifyChecksum(path, fields[0])
+}
+
+// verifyChecksum checks the file at PATH against the EXPECTED sha256.
+func verifyChecksum(path string, expected string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("sha256 mismatch for %s: expected %s, got %s", path, expected, actual)
+	}
+
+	c.Logger.Debugf("verified %s (sha256 %s)", path, actual)
+	return nil
+}
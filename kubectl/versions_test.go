@@ -0,0 +1,55 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestGroupVersions(t *testing.T) {
+	c.Logger = logrus.New()
+	tags := []string{"v1.27.0", "v1.28.1", "v1.28.9", "v1.29.3", "v1.29.10", "v2.0.0", "nightly"}
+	published := map[string]string{
+		"v1.27.0":  "2023-04-11T00:00:00Z",
+		"v1.28.1":  "2023-08-24T00:00:00Z",
+		"v1.28.9":  "2024-04-16T00:00:00Z",
+		"v1.29.3":  "2024-03-15T00:00:00Z",
+		"v1.29.10": "2024-10-22T00:00:00Z",
+		"v2.0.0":   "2025-01-01T00:00:00Z",
+	}
+
+	tests := []struct {
+		name    string
+		opts    listOptions
+		want    []string
+		wantErr bool
+	}{
+		{"minor by default", listOptions{}, []string{"v1.27.0", "v1.28.9", "v1.29.10", "v2.0.0"}, false},
+		{"all", listOptions{All: true}, []string{"v1.27.0", "v1.28.1", "v1.28.9", "v1.29.3", "v1.29.10", "v2.0.0"}, false},
+		{"major", listOptions{GroupBy: "major"}, []string{"v1.29.10", "v2.0.0"}, false},
+		{"prefix", listOptions{VerMatch: "v1.28"}, []string{"v1.28.9"}, false},
+		{"limit keeps the newest", listOptions{Limit: 2}, []string{"v1.29.10", "v2.0.0"}, false},
+		{"limit with reverse", listOptions{Limit: 2, Reverse: true}, []string{"v2.0.0", "v1.29.10"}, false},
+		{"reverse", listOptions{Reverse: true}, []string{"v2.0.0", "v1.29.10", "v1.28.9", "v1.27.0"}, false},
+		{"since", listOptions{Since: "2024-04-01", GroupBy: "none"}, []string{"v1.28.9", "v1.29.10", "v2.0.0"}, false},
+		{"bad group by", listOptions{GroupBy: "patch"}, nil, true},
+		{"bad since", listOptions{Since: "yesterday"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := groupVersions(tags, published, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("groupVersions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("groupVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := groupVersions(tags, map[string]string{}, listOptions{Since: "2024-01-01"}); err == nil {
+		t.Fatal("groupVersions() with --since and no release dates should fail")
+	}
+}
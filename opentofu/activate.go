@@ -14,23 +14,43 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of opentofu", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	path := filepath.Join(binPath, fmt.Sprintf("opentofu/%s/tofu", ver))
 	if !fileExists(path) {
+		if err := validateVersion(ver); err != nil {
+			return err
+		}
+
 		c.Logger.Infof("Downloading version %s of opentofu to path %s", ver, path)
-		err := downloadArtifact(ver, path)
+		err := stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(ver, stagedPath)
+		})
 		if err != nil {
-			c.Logger.WithError(err).Error("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of opentofu", ver)
 	err := changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -42,7 +62,7 @@ func fileExists(path string) bool {
 func downloadArtifact(semver, path string) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -51,11 +71,11 @@ func downloadArtifact(semver, path string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm64"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	if targetOS == "darwin" && targetArch == "arm" {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	// https://github.com/opentofu/opentofu/releases/download/v1.7.1/tofu_1.7.1_darwin_amd64.zip
@@ -66,22 +86,35 @@ func downloadArtifact(semver, path string) error {
 	// https://github.com/opentofu/opentofu/releases/download/v1.7.1/tofu_1.7.1_linux_arm64.tar.gz
 
 	realsemver := strings.TrimPrefix(semver, "v")
-	url := fmt.Sprintf("https://github.com/opentofu/opentofu/releases/download/%s/tofu_%s_%s_%s.tar.gz", semver, realsemver, targetOS, targetArch)
+	archiveName := fmt.Sprintf("tofu_%s_%s_%s.tar.gz", realsemver, targetOS, targetArch)
+	url := fmt.Sprintf("%s/opentofu/opentofu/releases/download/%s/%s", githubServerURL(), semver, archiveName)
 	c.Logger.Debugf("downloading %s", url)
 
 	client := resty.New()
-	resp, err := client.R().SetOutput(fmt.Sprintf("%s.tar.gz", path)).Get(url)
+	resp, err := githubAssetRequest(client, url).SetOutput(fmt.Sprintf("%s.tar.gz", path)).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading opentofu: %v", err)
+		return fmt.Errorf("%w: error downloading opentofu: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: opentofu %s", ErrVersionNotFound, semver)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading opentofu: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading opentofu: status code %d", ErrDownloadFailed, resp.StatusCode())
+	}
+
+	// verify archive
+	if err := verifyArchive(semver, archiveName, fmt.Sprintf("%s.tar.gz", path)); err != nil {
+		if rerr := os.Remove(fmt.Sprintf("%s.tar.gz", path)); rerr != nil {
+			c.Logger.WithError(rerr).Error("failed to remove archive file")
+		}
+		return fmt.Errorf("%w: opentofu: %w", ErrVerifyFailed, err)
 	}
 
 	// extract archive
 	if err := extractTarGz(fmt.Sprintf("%s.tar.gz", path), filepath.Dir(path)); err != nil {
-		return fmt.Errorf("error extracting helm: %v", err)
+		return fmt.Errorf("error extracting opentofu: %w", err)
 	}
 
 	return nil
@@ -116,30 +149,9 @@ func extractTarGz(tarGzPath, destDir string) error {
 			return err
 		}
 
-		filePath := filepath.Join(destDir, header.Name)
-
 		// extract all the files
-		// Check the type of the file
-		switch header.Typeflag {
-		case tar.TypeDir:
-			// Create the directory
-			if err := os.MkdirAll(filePath, os.FileMode(header.Mode)); err != nil {
-				return err
-			}
-		case tar.TypeReg:
-			// Create the file
-			outFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-			if err != nil {
-				return err
-			}
-			defer outFile.Close()
-
-			// Copy the file data from the tar archive
-			if _, err := io.Copy(outFile, tr); err != nil {
-				return err
-			}
-		default:
-			return fmt.Errorf("unsupported file type: %v", header.Typeflag)
+		if err := extractTarEntry(tr, header, destDir, header.Name, header.Linkname); err != nil {
+			return err
 		}
 	}
 	derr := os.Remove(tarGzPath)
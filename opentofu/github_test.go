@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func TestGithubAssetRequest(t *testing.T) {
+	c.Logger = logrus.New()
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GITHUB_API_URL", "")
+
+	tests := []struct {
+		name      string
+		serverURL string
+		url       string
+		want      string
+	}{
+		{"github.com asset", "", "https://github.com/opentofu/opentofu/releases/download/v1.7.1/tofu_1.7.1_SHA256SUMS", ""},
+		{"enterprise asset", "https://ghe.example.com", "https://ghe.example.com/opentofu/opentofu/releases/download/v1.7.1/tofu_1.7.1_SHA256SUMS", "Bearer secret"},
+		{"other host on enterprise", "https://ghe.example.com", "https://get.opentofu.org/opentofu.asc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_SERVER_URL", tt.serverURL)
+			req := githubAssetRequest(resty.New(), tt.url)
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Fatalf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
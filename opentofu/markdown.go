@@ -0,0 +1,81 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	mdLinkRe   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRe   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdCodeRe   = regexp.MustCompile("`([^`]+)`")
+	mdHeaderRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdBulletRe = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+)
+
+// renderMarkdown renders the markdown release notes MD for a terminal. Headers, bold text, inline
+// code and code blocks are coloured, bullets are indented and links show their URL. HTML comments,
+// which GitHub release templates often contain, are dropped.
+func renderMarkdown(md string) string {
+	header := color.New(color.FgCyan, color.Bold).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+	code := color.New(color.FgYellow).SprintFunc()
+	link := color.New(color.FgBlue, color.Underline).SprintFunc()
+
+	inline := func(line string) string {
+		line = mdCodeRe.ReplaceAllStringFunc(line, func(m string) string {
+			return code(strings.Trim(m, "`"))
+		})
+		line = mdBoldRe.ReplaceAllStringFunc(line, func(m string) string {
+			return bold(strings.Trim(m, "*_"))
+		})
+		return mdLinkRe.ReplaceAllStringFunc(line, func(m string) string {
+			parts := mdLinkRe.FindStringSubmatch(m)
+			if parts[1] == parts[2] {
+				return link(parts[2])
+			}
+			return parts[1] + " (" + link(parts[2]) + ")"
+		})
+	}
+
+	var out strings.Builder
+	inCode, inComment := false, false
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if inComment {
+			if strings.Contains(trimmed, "-->") {
+				inComment = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "<!--") {
+			inComment = !strings.Contains(trimmed, "-->")
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out.WriteString("    " + code(line) + "\n")
+			continue
+		}
+
+		if m := mdHeaderRe.FindStringSubmatch(trimmed); m != nil {
+			out.WriteString(header(inline(m[2])) + "\n")
+			continue
+		}
+		if m := mdBulletRe.FindStringSubmatch(line); m != nil {
+			out.WriteString(m[1] + "  • " + inline(m[2]) + "\n")
+			continue
+		}
+		out.WriteString(inline(line) + "\n")
+	}
+
+	return strings.TrimRight(out.String(), "\n")
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fatih/color"
+)
+
+// releaseNote holds the notes of one opentofu release.
+type releaseNote struct {
+	Tag       string
+	Published string
+	Body      string
+}
+
+// showNotes prints the notes of release VER, or when VER is empty the notes of every release after
+// FROM up to and including TO. FROM defaults to the active version and TO to the newest stable
+// release. Prereleases inside the range are left out.
+func showNotes(ver, from, to string) error {
+	notes, err := getReleaseNotes()
+	if err != nil {
+		return err
+	}
+
+	tags := []string{}
+	for _, note := range notes {
+		tags = append(tags, note.Tag)
+	}
+
+	if len(ver) > 0 {
+		note, err := findNote(notes, tags, ver)
+		if err != nil {
+			return err
+		}
+		printNote(note)
+		return nil
+	}
+
+	if len(from) == 0 {
+		from = activeVersion()
+		if len(from) == 0 {
+			return fmt.Errorf("no opentofu version is active, use --from or -v")
+		}
+	}
+	if len(to) == 0 {
+		to = latestStableVersion
+	}
+
+	fromNote, err := findNote(notes, tags, from)
+	if err != nil {
+		return err
+	}
+	toNote, err := findNote(notes, tags, to)
+	if err != nil {
+		return err
+	}
+	fromVer, err := semver.NewVersion(fromNote.Tag)
+	if err != nil {
+		return fmt.Errorf("%s is not a semver version", fromNote.Tag)
+	}
+	toVer, err := semver.NewVersion(toNote.Tag)
+	if err != nil {
+		return fmt.Errorf("%s is not a semver version", toNote.Tag)
+	}
+	if !toVer.GreaterThan(fromVer) {
+		return fmt.Errorf("opentofu %s is not newer than %s", toNote.Tag, fromNote.Tag)
+	}
+
+	type versionNote struct {
+		semver *semver.Version
+		note   releaseNote
+	}
+	between := []versionNote{}
+	for _, note := range notes {
+		nv, err := semver.NewVersion(note.Tag)
+		if err != nil {
+			continue
+		}
+		if !nv.GreaterThan(fromVer) || nv.GreaterThan(toVer) {
+			continue
+		}
+		if len(nv.Prerelease()) > 0 && !nv.Equal(toVer) {
+			continue
+		}
+		between = append(between, versionNote{semver: nv, note: note})
+	}
+	sort.SliceStable(between, func(i, j int) bool {
+		return between[i].semver.LessThan(between[j].semver)
+	})
+
+	for _, vn := range between {
+		printNote(vn.note)
+	}
+	return nil
+}
+
+// findNote returns the notes of VER, which may also be a constraint such as latest.
+func findNote(notes []releaseNote, tags []string, ver string) (releaseNote, error) {
+	if isVersionConstraint(ver) {
+		resolved, err := newestMatch(ver, tags)
+		if err != nil {
+			return releaseNote{}, err
+		}
+		ver = resolved
+	}
+
+	for _, note := range notes {
+		if normalizeVersion(note.Tag) == normalizeVersion(ver) {
+			return note, nil
+		}
+	}
+	return releaseNote{}, versionNotFound(normalizeVersion(ver), tags)
+}
+
+// printNote prints the header and the rendered notes of one release.
+func printNote(note releaseNote) {
+	title := color.New(color.FgGreen, color.Bold).SprintFunc()
+	if len(note.Published) > 0 {
+		fmt.Printf("%s\n\n", title(fmt.Sprintf("== %s (%s)", note.Tag, note.Published)))
+	} else {
+		fmt.Printf("%s\n\n", title(fmt.Sprintf("== %s", note.Tag)))
+	}
+
+	if len(strings.TrimSpace(note.Body)) == 0 {
+		fmt.Printf("(no release notes published)\n\n")
+		return
+	}
+	fmt.Printf("%s\n\n", renderMarkdown(note.Body))
+}
+
+// getReleaseNotes returns the notes of every published opentofu release, from the opentofu/opentofu GitHub
+// releases.
+func getReleaseNotes() ([]releaseNote, error) {
+	releases, err := getGitHubReleases("opentofu", "opentofu")
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read opentofu releases: %w", ErrReleasesUnavailable, err)
+	}
+
+	notes := []releaseNote{}
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		note := releaseNote{Tag: release.TagName, Body: release.Body}
+		if !release.PublishedAt.IsZero() {
+			note.Published = release.PublishedAt.Format(time.DateOnly)
+		}
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
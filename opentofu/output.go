@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+// versionEntry is one version in the output of the versions command.
+type versionEntry struct {
+	Tag        string `json:"tag" yaml:"tag"`
+	Version    string `json:"version" yaml:"version"`
+	Published  string `json:"published,omitempty" yaml:"published,omitempty"`
+	Prerelease bool   `json:"prerelease" yaml:"prerelease"`
+	Installed  bool   `json:"installed" yaml:"installed"`
+	Active     bool   `json:"active" yaml:"active"`
+}
+
+// newVersionEntries builds the output entries for TAGS, PUBLISHED maps a tag to when it was published.
+func newVersionEntries(tags []string, published map[string]string) []versionEntry {
+	active := activeVersion()
+	entries := make([]versionEntry, 0, len(tags))
+	for _, tag := range tags {
+		entry := versionEntry{
+			Tag:       tag,
+			Version:   normalizeVersion(tag),
+			Published: published[tag],
+			Installed: isInstalled(tag),
+			Active:    active != "" && normalizeVersion(tag) == active,
+		}
+		if nv, err := semver.NewVersion(tag); err == nil {
+			entry.Version = nv.String()
+			entry.Prerelease = len(nv.Prerelease()) > 0
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// printVersions writes ENTRIES to stdout in FORMAT, one of text, json, yaml or table. The text format
+// marks the active version with "->" and other installed versions with "*".
+func printVersions(entries []versionEntry, format string) error {
+	switch format {
+	case "", "text":
+		active := color.New(color.FgGreen, color.Bold).SprintFunc()
+		installed := color.New(color.FgCyan).SprintFunc()
+		for _, e := range entries {
+			switch {
+			case e.Active:
+				fmt.Printf("%s %s\n", active("->"), active(e.Tag))
+			case e.Installed:
+				fmt.Printf("%s %s\n", installed(" *"), e.Tag)
+			default:
+				fmt.Printf("   %s\n", e.Tag)
+			}
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(entries); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TAG\tVERSION\tPUBLISHED\tPRERELEASE\tINSTALLED\tACTIVE")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%t\t%t\n", e.Tag, e.Version, e.Published, e.Prerelease, e.Installed, e.Active)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q, use text, json, yaml or table", format)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+func TestCheckSumsSignature(t *testing.T) {
+	signer, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := openpgp.NewEntity("other", "", "other@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sums := []byte("aaa  tofu_1.7.1_linux_amd64.tar.gz\n")
+
+	var binarySig, armoredSig bytes.Buffer
+	if err := openpgp.DetachSign(&binarySig, signer, bytes.NewReader(sums), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := openpgp.ArmoredDetachSign(&armoredSig, signer, bytes.NewReader(sums), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		keyring openpgp.EntityList
+		sums    []byte
+		sig     []byte
+		wantErr bool
+	}{
+		{"binary signature", openpgp.EntityList{signer}, sums, binarySig.Bytes(), false},
+		{"armored signature", openpgp.EntityList{signer}, sums, armoredSig.Bytes(), false},
+		{"armored signature with leading whitespace", openpgp.EntityList{signer}, sums, append([]byte("\n"), armoredSig.Bytes()...), false},
+		{"tampered sums", openpgp.EntityList{signer}, []byte("bbb  tofu_1.7.1_linux_amd64.tar.gz\n"), binarySig.Bytes(), true},
+		{"unknown signer", openpgp.EntityList{other}, sums, armoredSig.Bytes(), true},
+		{"garbage signature", openpgp.EntityList{signer}, sums, []byte("not a signature"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity, err := checkSumsSignature(tt.keyring, tt.sums, tt.sig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSumsSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && entity.PrimaryKey.KeyId != signer.PrimaryKey.KeyId {
+				t.Fatalf("checkSumsSignature() signer = %X, want %X", entity.PrimaryKey.Fingerprint, signer.PrimaryKey.Fingerprint)
+			}
+		})
+	}
+}
@@ -14,32 +14,62 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of teleport", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	releaseTags, err := getTeleportDownloads()
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to read github releases")
+		return err
+	}
+
+	// the cached downloads may be older than the release, look again before giving up
+	if _, ok := releaseTags[ver]; !ok && !c.Refresh {
+		c.Logger.Debugf("teleport %s is not in the cached downloads, fetching them again", ver)
+		if refreshed, rerr := refetch("downloads", fetchTeleportDownloads); rerr == nil {
+			releaseTags = refreshed
+		} else {
+			c.Logger.WithError(rerr).Warn("failed to fetch the teleport downloads again")
+		}
 	}
 
 	if _, ok := releaseTags[ver]; !ok {
-		c.Logger.Fatalf("version %s not found, cannot download and activate", ver)
+		available := []string{}
+		for v := range releaseTags {
+			available = append(available, v)
+		}
+		return versionNotFound(ver, available)
 	}
 
 	path := filepath.Join(binPath, fmt.Sprintf("teleport/%s/teleport", ver))
 	if !fileExists(path) {
 		c.Logger.Infof("Downloading version %s of teleport to path %s", ver, path)
-		err := downloadArtifact(path, releaseTags[ver].Download)
+		err := stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(stagedPath, releaseTags[ver])
+		})
 		if err != nil {
-			c.Logger.WithError(err).Error("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of teleport", ver)
 	err = changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -47,11 +77,12 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-// DownloadArtifact downloads the teleport binary for the specified SEMVER and saves it to the given PATH.
-func downloadArtifact(path string, url string) error {
+// DownloadArtifact downloads the teleport archive for the given RELEASE, verifies it against the
+// published SHA-256 and size, and extracts the binaries next to the given PATH.
+func downloadArtifact(path string, release ReleaseDownload) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -60,24 +91,46 @@ func downloadArtifact(path string, url string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm64"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	// url := fmt.Sprintf("https://storage.googleapis.com/kubernetes-release/release/%s/bin/%s/%s/teleport", semver, targetOS, targetArch)
 
+	url := release.Download
 	client := resty.New()
 	resp, err := client.R().SetOutput(fmt.Sprintf("%s.tar.gz", path)).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading teleport: %v", err)
+		return fmt.Errorf("%w: error downloading teleport: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: teleport %s", ErrVersionNotFound, release.ReleaseTag)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading teleport: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading teleport: status code %d", ErrDownloadFailed, resp.StatusCode())
+	}
+
+	// the github release source does not know the checksum, the CDN publishes it next to the archive
+	expectedSha256 := release.Sha256
+	if len(expectedSha256) == 0 {
+		expectedSha256, err = fetchPublishedSha256(url)
+		if err != nil {
+			c.Logger.WithError(err).Warn("failed to read the published sha256")
+		}
+	}
+
+	// verify archive
+	if err := verifyChecksum(fmt.Sprintf("%s.tar.gz", path), expectedSha256, release.Size); err != nil {
+		if rerr := os.Remove(fmt.Sprintf("%s.tar.gz", path)); rerr != nil {
+			c.Logger.WithError(rerr).Error("failed to remove archive file")
+		}
+		return fmt.Errorf("%w: teleport: %w", ErrVerifyFailed, err)
 	}
 
 	// extract archive
 	if err := extractTarGz(fmt.Sprintf("%s.tar.gz", path), filepath.Dir(path)); err != nil {
-		return fmt.Errorf("error extracting teleport: %v", err)
+		return fmt.Errorf("error extracting teleport: %w", err)
 	}
 
 	return nil
@@ -112,33 +165,14 @@ func extractTarGz(tarGzPath, destDir string) error {
 			return err
 		}
 
-		// Check if the file is inside the "teleport" directory
-		if strings.HasPrefix(header.Name, "teleport/t") {
-			// Construct the full path for the file, excluding the "teleport/" prefix
-			filePath := filepath.Join(destDir, strings.TrimPrefix(header.Name, "teleport/"))
-
-			// Check the type of the file
-			switch header.Typeflag {
-			case tar.TypeDir:
-				// Create the directory
-				if err := os.MkdirAll(filePath, os.FileMode(header.Mode)); err != nil {
-					return err
-				}
-			case tar.TypeReg:
-				// Create the file
-				outFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-				if err != nil {
-					return err
-				}
-				defer outFile.Close()
-
-				// Copy the file data from the tar archive
-				if _, err := io.Copy(outFile, tr); err != nil {
-					return err
-				}
-			default:
-				return fmt.Errorf("unsupported file type: %v", header.Typeflag)
-			}
+		// Only extract the binaries inside the "teleport" directory, without the "teleport/" prefix
+		if !strings.HasPrefix(header.Name, "teleport/t") {
+			continue
+		}
+		name := strings.TrimPrefix(header.Name, "teleport/")
+
+		if err := extractTarEntry(tr, header, destDir, name, strings.TrimPrefix(header.Linkname, "teleport/")); err != nil {
+			return err
 		}
 	}
 	derr := os.Remove(tarGzPath)
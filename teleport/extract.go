@@ -0,0 +1,169 @@
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extractTarEntry writes the tar entry HEADER to NAME below DESTDIR. LINKNAME is the hardlink target
+// relative to DESTDIR, callers strip the same prefix from it as from NAME. Entries, symlink targets
+// and hardlink targets that would end up outside DESTDIR are rejected.
+func extractTarEntry(tr *tar.Reader, header *tar.Header, destDir string, name string, linkName string) error {
+	filePath, err := safeJoin(destDir, name)
+	if err != nil {
+		return err
+	}
+
+	if err := checkNoSymlinkParents(destDir, filePath); err != nil {
+		return err
+	}
+
+	mode := header.FileInfo().Mode().Perm()
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(filePath, 0755); err != nil {
+			return err
+		}
+		return os.Chmod(filePath, mode|0700)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		return writeFile(filePath, tr, mode)
+	case tar.TypeSymlink:
+		// the target is resolved relative to the directory holding the link
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		if err := checkSymlinkTarget(destDir, filepath.Dir(filePath), header.Linkname); err != nil {
+			return fmt.Errorf("illegal symlink target: %s -> %s: %w", header.Name, header.Linkname, err)
+		}
+		if err := removeExisting(filePath); err != nil {
+			return err
+		}
+		return os.Symlink(header.Linkname, filePath)
+	case tar.TypeLink:
+		targetPath, err := safeJoin(destDir, linkName)
+		if err != nil {
+			return fmt.Errorf("illegal hardlink target: %s -> %s", header.Name, header.Linkname)
+		}
+		if err := checkNoSymlinkParents(destDir, targetPath); err != nil {
+			return err
+		}
+		if info, err := os.Lstat(targetPath); err != nil || !info.Mode().IsRegular() {
+			return fmt.Errorf("hardlink target is not a regular file: %s -> %s", header.Name, header.Linkname)
+		}
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		if err := removeExisting(filePath); err != nil {
+			return err
+		}
+		return os.Link(targetPath, filePath)
+	default:
+		c.Logger.Debugf("skipping %s, unsupported file type: %v", header.Name, header.Typeflag)
+		return nil
+	}
+}
+
+// safeJoin joins NAME onto DESTDIR and refuses results outside of DESTDIR (ZipSlip).
+func safeJoin(destDir string, name string) (string, error) {
+	cleanDest := filepath.Clean(destDir)
+	target := filepath.Join(cleanDest, name)
+	if target != cleanDest && !strings.HasPrefix(target, cleanDest+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal file path: %s", name)
+	}
+	return target, nil
+}
+
+// checkNoSymlinkParents makes sure no directory between DESTDIR and TARGET is a symlink, an earlier
+// entry could otherwise redirect later writes outside of DESTDIR.
+func checkNoSymlinkParents(destDir string, target string) error {
+	cleanDest := filepath.Clean(destDir)
+	for dir := filepath.Dir(target); dir != cleanDest && strings.HasPrefix(dir, cleanDest); dir = filepath.Dir(dir) {
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("illegal file path: %s passes through symlink %s", target, dir)
+		}
+	}
+	return nil
+}
+
+// checkSymlinkTarget walks TARGET from DIR, the directory holding the link, the way it is resolved
+// on disk. A ".." may only follow a real directory: after a symlink, or a component that is not there
+// yet, it could climb out of DESTDIR even though the cleaned path stays inside.
+func checkSymlinkTarget(destDir string, dir string, target string) error {
+	if filepath.IsAbs(target) {
+		return fmt.Errorf("absolute target")
+	}
+
+	cleanDest := filepath.Clean(destDir)
+	current := dir
+	for _, part := range strings.Split(filepath.ToSlash(target), "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if current == cleanDest {
+				return fmt.Errorf("target leaves %s", cleanDest)
+			}
+			info, err := os.Lstat(current)
+			if err != nil || !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", current)
+			}
+			current = filepath.Dir(current)
+		default:
+			current = filepath.Join(current, part)
+		}
+	}
+	return nil
+}
+
+// writeFile copies the entry data to PATH and closes it right away, the mode is set explicitly so the
+// umask does not strip the executable bits.
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := removeExisting(path); err != nil {
+		return err
+	}
+
+	outFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(outFile, r); err != nil {
+		outFile.Close()
+		return err
+	}
+
+	if err := outFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, mode)
+}
+
+// removeExisting removes whatever is at PATH so a link or file from the archive replaces it rather
+// than writing through it. Directories are never replaced, links checked against them earlier would
+// resolve differently afterwards.
+func removeExisting(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil
+	}
+	if info.IsDir() {
+		return fmt.Errorf("illegal file path: %s would replace a directory", path)
+	}
+	return os.Remove(path)
+}
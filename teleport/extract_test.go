@@ -0,0 +1,94 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	body     string
+}
+
+func buildTar(t *testing.T, entries []tarEntry) *tar.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0755, Size: int64(len(e.body))}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return tar.NewReader(&buf)
+}
+
+func TestExtractTarEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		wantErr bool
+	}{
+		{"regular file", []tarEntry{{name: "bin/tool", typeflag: tar.TypeReg, body: "x"}}, false},
+		{"relative symlink inside", []tarEntry{{name: "bin/tool", typeflag: tar.TypeReg, body: "x"}, {name: "tool", typeflag: tar.TypeSymlink, linkname: "bin/tool"}}, false},
+		{"hardlink inside", []tarEntry{{name: "bin/tool", typeflag: tar.TypeReg, body: "x"}, {name: "tool", typeflag: tar.TypeLink, linkname: "bin/tool"}}, false},
+		{"dot dot entry", []tarEntry{{name: "../evil", typeflag: tar.TypeReg, body: "x"}}, true},
+		{"nested dot dot entry", []tarEntry{{name: "bin/../../evil", typeflag: tar.TypeReg, body: "x"}}, true},
+		{"absolute symlink target", []tarEntry{{name: "link", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"}}, true},
+		{"escaping symlink target", []tarEntry{{name: "bin/link", typeflag: tar.TypeSymlink, linkname: "../../evil"}}, true},
+		{"write through symlinked parent", []tarEntry{{name: "dir", typeflag: tar.TypeSymlink, linkname: "."}, {name: "dir/evil", typeflag: tar.TypeReg, body: "x"}}, true},
+		{"symlink to parent inside", []tarEntry{{name: "bin/tool", typeflag: tar.TypeReg, body: "x"}, {name: "lib/tool", typeflag: tar.TypeSymlink, linkname: "../bin/tool"}}, false},
+		{"dot dot after a symlink", []tarEntry{{name: "s", typeflag: tar.TypeSymlink, linkname: "."}, {name: "t", typeflag: tar.TypeSymlink, linkname: "s/../evil"}}, true},
+		{"dot dot after a missing component", []tarEntry{{name: "t", typeflag: tar.TypeSymlink, linkname: "s/../evil"}, {name: "s", typeflag: tar.TypeSymlink, linkname: "."}}, true},
+		{"directory replaced by a symlink", []tarEntry{{name: "d/", typeflag: tar.TypeDir}, {name: "t", typeflag: tar.TypeSymlink, linkname: "d/../evil"}, {name: "d", typeflag: tar.TypeSymlink, linkname: "."}}, true},
+		{"escaping hardlink", []tarEntry{{name: "link", typeflag: tar.TypeLink, linkname: "../evil"}}, true},
+		{"hardlink to symlink", []tarEntry{{name: "sym", typeflag: tar.TypeSymlink, linkname: "."}, {name: "link", typeflag: tar.TypeLink, linkname: "sym"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			destDir := filepath.Join(root, "dest")
+			if err := os.Mkdir(destDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(root, "evil"), []byte("original"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			tr := buildTar(t, tt.entries)
+			var err error
+			for {
+				header, nextErr := tr.Next()
+				if nextErr == io.EOF {
+					break
+				}
+				if nextErr != nil {
+					t.Fatal(nextErr)
+				}
+				if err = extractTarEntry(tr, header, destDir, header.Name, header.Linkname); err != nil {
+					break
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractTarEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if data, _ := os.ReadFile(filepath.Join(root, "evil")); string(data) != "original" {
+				t.Fatalf("file outside of the destination was modified: %q", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// teleportDownloadPage is the goteleport.com download page, a Next.js page that embeds its data.
+	teleportDownloadPage = "https://goteleport.com/download/"
+	// teleportCDNURL is the archive every teleport release publishes, by version, os and arch.
+	teleportCDNURL = "https://cdn.teleport.dev/teleport-v%s-%s-%s-bin.tar.gz"
+)
+
+// releaseSource is one place the teleport downloads for a platform can be discovered.
+type releaseSource struct {
+	name  string
+	fetch func(targetOS, targetArch string) (map[string]ReleaseDownload, error)
+}
+
+// releaseSources are tried in order until one returns downloads. The website sources carry the
+// checksums, sizes and release notes, the GitHub source only knows the versions.
+var releaseSources = []releaseSource{
+	{name: "download.json", fetch: fetchDownloadJSON},
+	{name: "download page", fetch: fetchDownloadPage},
+	{name: "github releases", fetch: fetchGitHubDownloads},
+}
+
+// fetchTeleportDownloads reads the downloads for this platform from the first release source that
+// has any.
+func fetchTeleportDownloads() (map[string]ReleaseDownload, error) {
+
+	targetOS := runtime.GOOS
+	if targetOS != "linux" && targetOS != "darwin" {
+		return map[string]ReleaseDownload{}, fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
+	}
+
+	targetArch := runtime.GOARCH
+	if targetArch == "amd64" {
+		targetArch = "amd64"
+	} else if targetArch == "arm64" {
+		targetArch = "arm64"
+	} else {
+		return map[string]ReleaseDownload{}, fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
+	}
+
+	var errs []error
+	for _, source := range releaseSources {
+		releaseInfo, err := source.fetch(targetOS, targetArch)
+		if err == nil && len(releaseInfo) == 0 {
+			err = fmt.Errorf("no downloads for %s/%s", targetOS, targetArch)
+		}
+		if err != nil {
+			c.Logger.WithError(err).Warnf("teleport release source %s failed", source.name)
+			errs = append(errs, fmt.Errorf("%s: %w", source.name, err))
+			continue
+		}
+		c.Logger.Debugf("read %d teleport downloads from %s", len(releaseInfo), source.name)
+		return releaseInfo, nil
+	}
+
+	return map[string]ReleaseDownload{}, fmt.Errorf("%w: every teleport release source failed: %w", ErrReleasesUnavailable, errors.Join(errs...))
+}
+
+// fetchDownloadJSON finds the current Next.js buildId and reads _next/data/<buildId>/download.json.
+func fetchDownloadJSON(targetOS, targetArch string) (map[string]ReleaseDownload, error) {
+	client := resty.New()
+	downloadURL := "https://goteleport.com/_next/data/latest/download.json"
+
+	response, err := client.R().Get(downloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", downloadURL, err)
+	}
+
+	body := string(response.Body())
+	// Unescape HTML entities
+	unescapedContent := html.UnescapeString(body)
+
+	// Regular expression to find the buildId value
+	re := regexp.MustCompile(`"buildId":"([^"]+)"`)
+
+	// Find the buildId value
+	buildId := ""
+	matches := re.FindStringSubmatch(unescapedContent)
+	if len(matches) > 1 {
+		buildId = matches[1]
+	} else {
+		return nil, fmt.Errorf("buildId not found")
+	}
+
+	url := fmt.Sprintf("https://goteleport.com/_next/data/%s/download.json", buildId)
+
+	resp, err := client.R().Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading download.json: %w", err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+	}
+
+	var releases Downloads
+	if err := json.Unmarshal(resp.Body(), &releases); err != nil {
+		return nil, err
+	}
+
+	return platformDownloads(releases.PageProps, targetOS, targetArch), nil
+}
+
+// fetchDownloadPage reads the page data from the __NEXT_DATA__ script of the download page, which
+// does not depend on knowing the buildId.
+func fetchDownloadPage(targetOS, targetArch string) (map[string]ReleaseDownload, error) {
+	resp, err := resty.New().R().Get(teleportDownloadPage)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", teleportDownloadPage, err)
+	}
+
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+	}
+
+	re := regexp.MustCompile(`(?s)<script id="__NEXT_DATA__"[^>]*>(.*?)</script>`)
+	matches := re.FindSubmatch(resp.Body())
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("__NEXT_DATA__ not found")
+	}
+
+	var nextData NextData
+	if err := json.Unmarshal(matches[1], &nextData); err != nil {
+		return nil, fmt.Errorf("failed to decode __NEXT_DATA__: %w", err)
+	}
+
+	return platformDownloads(nextData.Props.PageProps, targetOS, targetArch), nil
+}
+
+// fetchGitHubDownloads lists the gravitational/teleport releases and builds the download URL from
+// the CDN naming scheme. The checksum is read from the .sha256 next to the archive when downloading.
+func fetchGitHubDownloads(targetOS, targetArch string) (map[string]ReleaseDownload, error) {
+	releases, err := fetchGitHubReleases("gravitational", "teleport")
+	if err != nil {
+		return nil, err
+	}
+
+	releaseInfo := map[string]ReleaseDownload{}
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+		ver := normalizeVersion(release.TagName)
+		download := ReleaseDownload{
+			ReleaseTag: ver,
+			Download:   fmt.Sprintf(teleportCDNURL, ver, targetOS, targetArch),
+			NotesMd:    release.Body,
+		}
+		if !release.PublishedAt.IsZero() {
+			download.Status = release.PublishedAt.Format(time.RFC3339)
+		}
+		releaseInfo[ver] = download
+	}
+	return releaseInfo, nil
+}
+
+// platformDownloads picks the tar.gz archive for TARGETOS and TARGETARCH out of each version on the
+// download page.
+func platformDownloads(pageProps PageProps, targetOS, targetArch string) map[string]ReleaseDownload {
+	releaseInfo := map[string]ReleaseDownload{}
+	for _, release := range pageProps.InitialDownloads {
+		for _, version := range release.Versions {
+			for _, asset := range version.Assets {
+				if asset.OS == targetOS && asset.Arch == targetArch {
+					if strings.HasSuffix(asset.Name, ".tar.gz") {
+						releaseInfo[version.Version] = ReleaseDownload{
+							ReleaseTag: version.Version,
+							Download:   asset.PublicURL,
+							Sha256:     asset.Sha256,
+							Size:       asset.Size,
+							Status:     version.Status,
+							NotesMd:    version.NotesMd,
+						}
+					}
+				}
+			}
+		}
+	}
+	return releaseInfo
+}
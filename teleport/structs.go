@@ -1,8 +1,21 @@
 package main
 
+import "time"
+
 type ReleaseDownload struct {
 	ReleaseTag string `json:"release_tag"`
 	Download   string `json:"download"`
+	Sha256     string `json:"sha256"`
+	Size       int    `json:"size"`
+	Status     string `json:"status"`
+	NotesMd    string `json:"notes_md"`
+}
+
+type NextData struct {
+	BuildID string `json:"buildId"`
+	Props   struct {
+		PageProps PageProps `json:"pageProps"`
+	} `json:"props"`
 }
 
 type Downloads struct {
@@ -52,3 +65,57 @@ type PageProps struct {
 	InitialDownloads     []InitialDownloads   `json:"initialDownloads"`
 	RecommendedDownloads RecommendedDownloads `json:"recommendedDownloads"`
 }
+
+type GithubReleaseList []GithubRelease
+
+type GithubRelease struct {
+	URL             string        `json:"url"`
+	AssetsURL       string        `json:"assets_url"`
+	UploadURL       string        `json:"upload_url"`
+	HTMLURL         string        `json:"html_url"`
+	ID              int           `json:"id"`
+	Author          Author        `json:"author"`
+	NodeID          string        `json:"node_id"`
+	TagName         string        `json:"tag_name"`
+	TargetCommitish string        `json:"target_commitish"`
+	Name            string        `json:"name"`
+	Draft           bool          `json:"draft"`
+	Prerelease      bool          `json:"prerelease"`
+	CreatedAt       time.Time     `json:"created_at"`
+	PublishedAt     time.Time     `json:"published_at"`
+	Assets          []interface{} `json:"assets"`
+	TarballURL      string        `json:"tarball_url"`
+	ZipballURL      string        `json:"zipball_url"`
+	Body            string        `json:"body"`
+	Reactions       Reactions     `json:"reactions"`
+}
+type Author struct {
+	Login             string `json:"login"`
+	ID                int    `json:"id"`
+	NodeID            string `json:"node_id"`
+	AvatarURL         string `json:"avatar_url"`
+	GravatarID        string `json:"gravatar_id"`
+	URL               string `json:"url"`
+	HTMLURL           string `json:"html_url"`
+	FollowersURL      string `json:"followers_url"`
+	FollowingURL      string `json:"following_url"`
+	GistsURL          string `json:"gists_url"`
+	StarredURL        string `json:"starred_url"`
+	SubscriptionsURL  string `json:"subscriptions_url"`
+	OrganizationsURL  string `json:"organizations_url"`
+	ReposURL          string `json:"repos_url"`
+	EventsURL         string `json:"events_url"`
+	ReceivedEventsURL string `json:"received_events_url"`
+	Type              string `json:"type"`
+	SiteAdmin         bool   `json:"site_admin"`
+}
+type Reactions struct {
+	URL        string `json:"url"`
+	TotalCount int    `json:"total_count"`
+	Laugh      int    `json:"laugh"`
+	Hooray     int    `json:"hooray"`
+	Confused   int    `json:"confused"`
+	Heart      int    `json:"heart"`
+	Rocket     int    `json:"rocket"`
+	Eyes       int    `json:"eyes"`
+}
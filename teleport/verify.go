@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// verifyChecksum checks the file at PATH against the SHA-256 and size published in download.json,
+// or the .sha256 file on the CDN when the size is not known.
+// A missing checksum is treated as a failure, we never activate an archive we could not verify.
+func verifyChecksum(path string, expectedSha256 string, expectedSize int) error {
+	if len(expectedSha256) == 0 {
+		return fmt.Errorf("no sha256 published for %s", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return err
+	}
+
+	if expectedSize > 0 && size != int64(expectedSize) {
+		return fmt.Errorf("size mismatch for %s: expected %d bytes, got %d", path, expectedSize, size)
+	}
+
+	actualSha256 := hex.EncodeToString(hasher.Sum(nil))
+	if !strings.EqualFold(actualSha256, strings.TrimSpace(expectedSha256)) {
+		return fmt.Errorf("sha256 mismatch for %s: expected %s, got %s", path, expectedSha256, actualSha256)
+	}
+
+	c.Logger.Debugf("verified %s (sha256 %s, %d bytes)", path, actualSha256, size)
+	return nil
+}
+
+// fetchPublishedSha256 reads the <URL>.sha256 file published next to a teleport archive, which holds
+// the checksum followed by the archive name.
+func fetchPublishedSha256(url string) (string, error) {
+	resp, err := resty.New().R().Get(fmt.Sprintf("%s.sha256", url))
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode() != 200 {
+		return "", fmt.Errorf("unexpected status code %d for %s.sha256", resp.StatusCode(), url)
+	}
+
+	fields := strings.Fields(string(resp.Body()))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s.sha256 is empty", url)
+	}
+	return fields[0], nil
+}
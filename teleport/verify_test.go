@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// helloSha256 is the sha256 of "hello\n".
+const helloSha256 = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+
+func TestVerifyChecksum(t *testing.T) {
+	c.Logger = logrus.New()
+	path := filepath.Join(t.TempDir(), "teleport.tar.gz")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		sha256  string
+		size    int
+		wantErr bool
+	}{
+		{"match", helloSha256, 6, false},
+		{"match without size", helloSha256, 0, false},
+		{"match with trailing newline", helloSha256 + "\n", 6, false},
+		{"empty sha256", "", 6, true},
+		{"size mismatch", helloSha256, 7, true},
+		{"sha256 mismatch", "0000000000000000000000000000000000000000000000000000000000000000", 6, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyChecksum(path, tt.sha256, tt.size); (err != nil) != tt.wantErr {
+				t.Fatalf("verifyChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchPublishedSha256(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"digest and name", helloSha256 + "  teleport-v15.4.2-linux-amd64-bin.tar.gz\n", helloSha256, false},
+		{"bare digest", helloSha256 + "\n", helloSha256, false},
+		{"empty", "\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := fetchPublishedSha256(server.URL + "/teleport.tar.gz")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fetchPublishedSha256() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("fetchPublishedSha256() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
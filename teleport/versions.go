@@ -1,151 +1,197 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"html"
-	"regexp"
-	"runtime"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/go-resty/resty/v2"
 )
 
-func listVersions(verMatch string, all bool) {
+// listOptions are the flags of the versions command.
+type listOptions struct {
+	VerMatch   string
+	All        bool
+	Prerelease bool
+	Output     string
+	Installed  bool
+	GroupBy    string
+	Limit      int
+	Since      string
+	Reverse    bool
+}
 
-	releaseTags, err := getTeleportDownloads()
-	if err != nil {
-		c.Logger.WithError(err).Error("failed to read github releases")
-	}
+func listVersions(opts listOptions) error {
 
-	if all {
-		for _, v := range releaseTags {
-			if len(verMatch) > 0 {
-				if strings.HasPrefix(v.ReleaseTag, verMatch) {
-					fmt.Printf("%s\n", v.ReleaseTag)
+	releaseTags := map[string]ReleaseDownload{}
+	if opts.Installed {
+		installed, err := installedVersions()
+		if err != nil {
+			return err
+		}
+		for _, v := range installed {
+			releaseTags[v] = ReleaseDownload{ReleaseTag: v}
+		}
+	} else {
+		var err error
+		releaseTags, err = getTeleportDownloads()
+		if err != nil {
+			return err
+		}
+		if !opts.Prerelease {
+			for tag := range releaseTags {
+				if isPrerelease(tag) {
+					delete(releaseTags, tag)
 				}
-			} else {
-				fmt.Printf("%s\n", v.ReleaseTag)
 			}
 		}
-	} else {
-		justMinors(releaseTags, verMatch)
 	}
-}
-
-func justMinors(releaseTags map[string]ReleaseDownload, verMatch string) {
 
-	minorRelease := map[string]semver.Version{}
-	for _, v := range releaseTags {
-		nv, err := semver.NewVersion(v.ReleaseTag)
-		if err != nil {
-			c.Logger.Error(fmt.Sprintf("Error parsing SemVer for %s", v))
+	if isVersionConstraint(opts.VerMatch) {
+		available := []string{}
+		for v := range releaseTags {
+			available = append(available, v)
 		}
-		verKey := fmt.Sprintf("%d.%d", nv.Major(), nv.Minor())
-		processVer := true
-		if len(verMatch) > 0 {
-			if !strings.HasPrefix(v.ReleaseTag, verMatch) {
-				processVer = false
-			}
+		matched, err := matchVersions(opts.VerMatch, available)
+		if err != nil {
+			return err
 		}
-		if processVer {
-			if _, ok := minorRelease[verKey]; ok {
-				if minorRelease[verKey].Patch() < nv.Patch() {
-					minorRelease[verKey] = *nv
-				}
-			} else {
-				// add
-				minorRelease[verKey] = *nv
-			}
+		matchedTags := map[string]ReleaseDownload{}
+		for _, v := range matched {
+			matchedTags[v] = releaseTags[v]
 		}
+		releaseTags = matchedTags
+		opts.VerMatch = ""
 	}
-	vs := []*semver.Version{}
-	for _, v := range minorRelease {
-		vs = append(vs, &v)
+
+	available := []string{}
+	for _, v := range releaseTags {
+		available = append(available, v.ReleaseTag)
 	}
+	published := publishedDates(releaseTags)
 
-	sort.Sort(semver.Collection(vs))
+	selected, err := groupVersions(available, published, opts)
+	if err != nil {
+		return err
+	}
+	return printVersions(newVersionEntries(selected, published), opts.Output)
+}
 
-	// kubernetes/kubernetes uses a "v", so we should indicate so
-	for _, v := range vs {
-		fmt.Printf("%s\n", releaseTags[v.String()].ReleaseTag)
+// publishedDates maps the tags of RELEASETAGS to when they were published. Installed versions and
+// some sources carry no date, they are left out so --since can tell that dates are unknown.
+func publishedDates(releaseTags map[string]ReleaseDownload) map[string]string {
+	published := map[string]string{}
+	for _, v := range releaseTags {
+		if _, err := time.Parse(time.RFC3339, v.Status); err == nil {
+			published[v.ReleaseTag] = v.Status
+		}
 	}
+	return published
 }
 
-func getTeleportDownloads() (map[string]ReleaseDownload, error) {
+// isPrerelease reports whether TAG has a semver prerelease component, such as -rc.1 or -alpha.2.
+func isPrerelease(tag string) bool {
+	nv, err := semver.NewVersion(tag)
+	return err == nil && len(nv.Prerelease()) > 0
+}
 
-	targetOS := runtime.GOOS
-	if targetOS != "linux" && targetOS != "darwin" {
-		c.Logger.Fatal("failed to determine target os")
+// groupVersions filters RELEASETAGS by the prefix and --since date of OPTS and keeps the newest
+// version of each major or minor. The newest --limit versions are returned oldest first, or newest
+// first with --reverse. Tags that are not semver are skipped.
+func groupVersions(releaseTags []string, published map[string]string, opts listOptions) ([]string, error) {
+	groupBy := opts.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = "minor"
+		if opts.All {
+			groupBy = "none"
+		}
 	}
-
-	targetArch := runtime.GOARCH
-	if targetArch == "amd64" {
-		targetArch = "amd64"
-	} else if targetArch == "arm64" {
-		targetArch = "arm64"
-	} else {
-		c.Logger.Fatal("failed to determine target architecture")
+	if groupBy != "major" && groupBy != "minor" && groupBy != "none" {
+		return nil, fmt.Errorf("unknown --group-by %q, use major, minor or none", groupBy)
 	}
 
-	client := resty.New()
-	downloadURL := "https://goteleport.com/_next/data/latest/download.json"
-
-	response, err := client.R().Get(downloadURL)
-	if err != nil {
-		fmt.Printf("Error downloading file: %v\n", err)
-		return map[string]ReleaseDownload{}, err
+	var since time.Time
+	if len(opts.Since) > 0 {
+		var err error
+		since, err = parseSince(opts.Since)
+		if err != nil {
+			return nil, err
+		}
+		if len(published) == 0 {
+			return nil, fmt.Errorf("--since needs release dates, which are not known for these versions")
+		}
 	}
 
-	body := string(response.Body())
-	// Unescape HTML entities
-	unescapedContent := html.UnescapeString(body)
-
-	// Regular expression to find the buildId value
-	re := regexp.MustCompile(`"buildId":"([^"]+)"`)
+	groups := map[string]*semver.Version{}
+	for _, v := range releaseTags {
+		if len(opts.VerMatch) > 0 && !strings.HasPrefix(v, opts.VerMatch) {
+			continue
+		}
+		nv, err := semver.NewVersion(v)
+		if err != nil {
+			c.Logger.Debugf("skipping %s, not a semver version: %s", v, err)
+			continue
+		}
+		if !since.IsZero() {
+			publishedAt, err := time.Parse(time.RFC3339, published[v])
+			if err != nil || publishedAt.Before(since) {
+				continue
+			}
+		}
 
-	// Find the buildId value
-	buildId := ""
-	matches := re.FindStringSubmatch(unescapedContent)
-	if len(matches) > 1 {
-		buildId = matches[1]
-	} else {
-		return map[string]ReleaseDownload{}, fmt.Errorf("buildId not found")
+		verKey := v
+		switch groupBy {
+		case "major":
+			verKey = fmt.Sprintf("%d", nv.Major())
+		case "minor":
+			verKey = fmt.Sprintf("%d.%d", nv.Major(), nv.Minor())
+		}
+		if current, ok := groups[verKey]; !ok || nv.GreaterThan(current) {
+			groups[verKey] = nv
+		}
 	}
 
-	url := fmt.Sprintf("https://goteleport.com/_next/data/%s/download.json", buildId)
-
-	resp, err := client.R().Get(url)
-	if err != nil {
-		fmt.Printf("Error downloading download.json: %v\n", err)
-		return map[string]ReleaseDownload{}, err
+	// newest first, equal versions with different tags are ordered by tag so the output is stable
+	vs := []*semver.Version{}
+	for _, v := range groups {
+		vs = append(vs, v)
 	}
-
-	if resp.StatusCode() != 200 {
-		return map[string]ReleaseDownload{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+	sort.Slice(vs, func(i, j int) bool {
+		if vs[i].Equal(vs[j]) {
+			return vs[i].Original() > vs[j].Original()
+		}
+		return vs[i].GreaterThan(vs[j])
+	})
+	if opts.Limit > 0 && len(vs) > opts.Limit {
+		vs = vs[:opts.Limit]
+	}
+	if !opts.Reverse {
+		slices.Reverse(vs)
 	}
 
-	var releases Downloads
-	if err := json.Unmarshal(resp.Body(), &releases); err != nil {
-		return map[string]ReleaseDownload{}, err
+	tags := []string{}
+	for _, v := range vs {
+		tags = append(tags, v.Original())
 	}
+	return tags, nil
+}
 
-	releaseInfo := map[string]ReleaseDownload{}
-	for _, release := range releases.PageProps.InitialDownloads {
-		for _, version := range release.Versions {
-			for _, asset := range version.Assets {
-				if asset.OS == targetOS && asset.Arch == targetArch {
-					if strings.HasSuffix(asset.Name, ".tar.gz") {
-						releaseInfo[version.Version] = ReleaseDownload{
-							ReleaseTag: version.Version,
-							Download:   asset.PublicURL,
-						}
-					}
-				}
-			}
-		}
+// parseSince reads the --since date, either a day such as 2024-01-31 or an RFC 3339 time.
+func parseSince(since string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, since); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q, use a date such as 2024-01-31", since)
 	}
-	return releaseInfo, nil
+	return t, nil
+}
+
+// getTeleportDownloads returns the teleport downloads for this platform, from the on-disk cache while
+// it is fresh.
+func getTeleportDownloads() (map[string]ReleaseDownload, error) {
+	return cachedFetch("downloads", fetchTeleportDownloads)
 }
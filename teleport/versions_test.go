@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestPublishedDates(t *testing.T) {
+	releaseTags := map[string]ReleaseDownload{
+		"15.4.2": {ReleaseTag: "15.4.2", Status: "2024-04-30T12:00:00Z"},
+		"15.4.1": {ReleaseTag: "15.4.1", Status: ""},
+		"15.4.0": {ReleaseTag: "15.4.0", Status: "published"},
+	}
+
+	want := map[string]string{"15.4.2": "2024-04-30T12:00:00Z"}
+	if got := publishedDates(releaseTags); !reflect.DeepEqual(got, want) {
+		t.Fatalf("publishedDates() = %v, want %v", got, want)
+	}
+}
+
+func TestGroupVersionsSinceWithoutDates(t *testing.T) {
+	c.Logger = logrus.New()
+
+	releaseTags := map[string]ReleaseDownload{"15.4.2": {ReleaseTag: "15.4.2"}}
+	_, err := groupVersions([]string{"15.4.2"}, publishedDates(releaseTags), listOptions{Since: "2020-01-01"})
+	if err == nil {
+		t.Fatal("groupVersions() with --since and no release dates should fail")
+	}
+}
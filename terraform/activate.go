@@ -13,23 +13,44 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of terraform", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	path := filepath.Join(binPath, fmt.Sprintf("terraform/%s/terraform", ver))
 	if !fileExists(path) {
+		release, err := findRelease(ver)
+		if err != nil {
+			return err
+		}
+
 		c.Logger.Infof("Downloading version %s of terraform to path %s", ver, path)
-		err := downloadArtifact(ver, path)
+		err = stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(release, stagedPath)
+		})
 		if err != nil {
-			c.Logger.WithError(err).Error("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of terraform", ver)
 	err := changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -37,11 +58,12 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-// DownloadArtifact downloads the terraform binary for the specified SEMVER and saves it to the given PATH.
-func downloadArtifact(semver, path string) error {
+// DownloadArtifact downloads the terraform binary of RELEASE, from the build URL the releases API
+// gives for this platform, and saves it to the given PATH.
+func downloadArtifact(release HashicorpRelease, path string) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -50,19 +72,41 @@ func downloadArtifact(semver, path string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm64"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
-	url := fmt.Sprintf("https://releases.hashicorp.com/terraform/%s/terraform_%s_%s_%s.zip", semver, semver, targetOS, targetArch)
+	url := ""
+	for _, build := range release.Builds {
+		if build.OS == targetOS && build.Arch == targetArch {
+			url = build.URL
+			break
+		}
+	}
+	if len(url) == 0 {
+		return fmt.Errorf("%w: no terraform %s build for %s/%s", ErrUnsupportedPlatform, release.Version, targetOS, targetArch)
+	}
+	archiveName := url[strings.LastIndex(url, "/")+1:]
 
 	client := resty.New()
 	resp, err := client.R().SetOutput(fmt.Sprintf("%s.zip", path)).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading terraform: %v", err)
+		return fmt.Errorf("%w: error downloading terraform: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: terraform %s", ErrVersionNotFound, release.Version)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading terraform: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading terraform: status code %d", ErrDownloadFailed, resp.StatusCode())
+	}
+
+	// verify
+	if err := verifyArchive(release, archiveName, fmt.Sprintf("%s.zip", path)); err != nil {
+		if rerr := os.Remove(fmt.Sprintf("%s.zip", path)); rerr != nil {
+			c.Logger.WithError(rerr).Error("failed to remove archive file")
+		}
+		return fmt.Errorf("%w: terraform: %w", ErrVerifyFailed, err)
 	}
 
 	// extract
@@ -1,4 +1,9 @@
 #!/bin/bash
 
-go build -buildmode=plugin -ldflags="-s -w" -v
+# the HashiCorp key is embedded, refuse to build a plugin that cannot verify any download
+if ! grep -q "BEGIN PGP PUBLIC KEY BLOCK" hashicorp.asc; then
+  echo "hashicorp.asc does not hold the HashiCorp public key" >&2
+  exit 1
+fi
 
+go build -buildmode=plugin -ldflags="-s -w" -v
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// normalizeVersion returns VER the way terraform versions are activated, without a "v" prefix, as in 1.7.5.
+func normalizeVersion(ver string) string {
+	return strings.TrimPrefix(strings.TrimSpace(ver), "v")
+}
+
+// findRelease returns the published release of VER before anything is downloaded, when it is not
+// published the error lists the closest published versions. A version missing from the cached
+// releases is looked up again, it may have been published since they were fetched.
+func findRelease(ver string) (HashicorpRelease, error) {
+	releases, err := getTerraformReleases()
+	if err == nil && !c.Refresh && !hasVersion(releaseVersions(releases, true), ver) {
+		c.Logger.Debugf("terraform %s is not in the cached releases, fetching them again", ver)
+		if refreshed, rerr := refetch("releases", fetchTerraformReleases); rerr == nil {
+			releases = refreshed
+		} else {
+			c.Logger.WithError(rerr).Warn("failed to fetch the terraform releases again")
+		}
+	}
+	if err != nil {
+		return HashicorpRelease{}, fmt.Errorf("%w: failed to read terraform releases: %w", ErrReleasesUnavailable, err)
+	}
+
+	for _, release := range releases {
+		if normalizeVersion(release.Version) == ver {
+			return release, nil
+		}
+	}
+
+	return HashicorpRelease{}, versionNotFound(ver, releaseVersions(releases, true))
+}
+
+// hasVersion reports whether VER is one of TAGS once both are normalized.
+func hasVersion(tags []string, ver string) bool {
+	for _, v := range tags {
+		if normalizeVersion(v) == ver {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveConstraint returns the newest published terraform version matching the constraint EXPR.
+func resolveConstraint(expr string) (string, error) {
+	releaseTags, err := getTerraformVersions()
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read terraform releases: %w", ErrReleasesUnavailable, err)
+	}
+
+	return newestMatch(expr, releaseTags)
+}
+
+// stableVersions returns the published terraform versions, leaving out prereleases.
+func stableVersions() ([]string, error) {
+	releases, err := getTerraformReleases()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read terraform releases: %w", ErrReleasesUnavailable, err)
+	}
+
+	return releaseVersions(releases, false), nil
+}
+
+// versionNotFound builds the ErrVersionNotFound error for VER, with suggestions from AVAILABLE.
+func versionNotFound(ver string, available []string) error {
+	suggestions := closestVersions(ver, available, 5)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("%w: terraform %s", ErrVersionNotFound, ver)
+	}
+	return fmt.Errorf("%w: terraform %s, closest versions: %s", ErrVersionNotFound, ver, strings.Join(suggestions, ", "))
+}
+
+// closestVersions returns up to COUNT of the AVAILABLE versions nearest to VER. Versions are ranked
+// by how far apart their MAJOR, MINOR and PATCH are, or by common prefix when VER is not semver.
+func closestVersions(ver string, available []string, count int) []string {
+	type candidate struct {
+		version  string
+		semver   *semver.Version
+		distance uint64
+	}
+
+	target, terr := semver.NewVersion(ver)
+	candidates := []candidate{}
+	for _, v := range available {
+		nv := normalizeVersion(v)
+		if terr != nil {
+			prefix := commonPrefixLen(nv, ver)
+			if prefix > 0 {
+				candidates = append(candidates, candidate{version: nv, distance: uint64(len(ver) - prefix)})
+			}
+			continue
+		}
+		sv, err := semver.NewVersion(v)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			version: nv,
+			semver:  sv,
+			distance: absDiff(sv.Major(), target.Major())<<40 |
+				absDiff(sv.Minor(), target.Minor())<<20 |
+				absDiff(sv.Patch(), target.Patch()),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		if candidates[i].semver != nil && candidates[j].semver != nil {
+			return candidates[i].semver.GreaterThan(candidates[j].semver)
+		}
+		return candidates[i].version > candidates[j].version
+	})
+
+	suggestions := []string{}
+	seen := map[string]bool{}
+	for _, cand := range candidates {
+		if len(suggestions) == count {
+			break
+		}
+		if !seen[cand.version] {
+			seen[cand.version] = true
+			suggestions = append(suggestions, cand.version)
+		}
+	}
+	return suggestions
+}
+
+func absDiff(a, b uint64) uint64 {
+	diff := a - b
+	if b > a {
+		diff = b - a
+	}
+	// keep each component inside its 20 bits of the distance
+	if diff > 0xfffff {
+		diff = 0xfffff
+	}
+	return diff
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
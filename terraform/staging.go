@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// stagingPrefix names the directories downloads are staged in, next to the version directories.
+	stagingPrefix = ".staging-"
+	// stagingMaxAge is how old a staging directory must be before it is considered abandoned, so a
+	// concurrent activation does not have its staging directory removed from under it.
+	stagingMaxAge = time.Hour
+)
+
+// stageArtifact runs DOWNLOAD against a staging directory and, once it succeeds and the binary is
+// in place, renames the staging directory to the version directory holding PATH.
+func stageArtifact(path string, download func(stagedPath string) error) error {
+	versionDir := filepath.Dir(path)
+	toolDir := filepath.Dir(versionDir)
+
+	if err := os.MkdirAll(toolDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", toolDir, err)
+	}
+	cleanStagingDirs(toolDir)
+
+	stagingDir, err := os.MkdirTemp(toolDir, stagingPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	// a no-op once the rename below has happened
+	defer os.RemoveAll(stagingDir)
+
+	stagedPath := filepath.Join(stagingDir, filepath.Base(path))
+	if err := download(stagedPath); err != nil {
+		return err
+	}
+
+	if !fileExists(stagedPath) {
+		return fmt.Errorf("%w: %s missing after download", ErrDownloadFailed, filepath.Base(path))
+	}
+
+	// anything already at versionDir is a partial install from before downloads were staged
+	if fileExists(versionDir) {
+		c.Logger.Debugf("removing incomplete install %s", versionDir)
+		if err := os.RemoveAll(versionDir); err != nil {
+			return fmt.Errorf("failed to remove incomplete install: %w", err)
+		}
+	}
+
+	c.Logger.Debugf("moving %s to %s", stagingDir, versionDir)
+	if err := os.Rename(stagingDir, versionDir); err != nil {
+		return fmt.Errorf("failed to move staged download into place: %w", err)
+	}
+
+	return nil
+}
+
+// cleanStagingDirs removes staging directories left behind by interrupted downloads.
+func cleanStagingDirs(toolDir string) {
+	entries, err := os.ReadDir(toolDir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), stagingPrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < stagingMaxAge {
+			continue
+		}
+		stalePath := filepath.Join(toolDir, entry.Name())
+		c.Logger.Debugf("removing stale staging directory %s", stalePath)
+		if err := os.RemoveAll(stalePath); err != nil {
+			c.Logger.WithError(err).Warnf("failed to remove stale staging directory %s", stalePath)
+		}
+	}
+}
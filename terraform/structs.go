@@ -0,0 +1,77 @@
+package main
+
+import "time"
+
+type HashicorpRelease struct {
+	Name                 string           `json:"name"`
+	Version              string           `json:"version"`
+	IsPrerelease         bool             `json:"is_prerelease"`
+	LicenseClass         string           `json:"license_class"`
+	TimestampCreated     time.Time        `json:"timestamp_created"`
+	TimestampUpdated     time.Time        `json:"timestamp_updated"`
+	Builds               []HashicorpBuild `json:"builds"`
+	URLShasums           string           `json:"url_shasums"`
+	URLShasumsSignatures []string         `json:"url_shasums_signatures"`
+	URLChangelog         string           `json:"url_changelog"`
+	URLReleaseNotes      string           `json:"url_release_notes"`
+	URLSourceRepository  string           `json:"url_source_repository"`
+}
+type HashicorpBuild struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+	URL  string `json:"url"`
+}
+
+type GithubReleaseList []GithubRelease
+
+type GithubRelease struct {
+	URL             string        `json:"url"`
+	AssetsURL       string        `json:"assets_url"`
+	UploadURL       string        `json:"upload_url"`
+	HTMLURL         string        `json:"html_url"`
+	ID              int           `json:"id"`
+	Author          Author        `json:"author"`
+	NodeID          string        `json:"node_id"`
+	TagName         string        `json:"tag_name"`
+	TargetCommitish string        `json:"target_commitish"`
+	Name            string        `json:"name"`
+	Draft           bool          `json:"draft"`
+	Prerelease      bool          `json:"prerelease"`
+	CreatedAt       time.Time     `json:"created_at"`
+	PublishedAt     time.Time     `json:"published_at"`
+	Assets          []interface{} `json:"assets"`
+	TarballURL      string        `json:"tarball_url"`
+	ZipballURL      string        `json:"zipball_url"`
+	Body            string        `json:"body"`
+	Reactions       Reactions     `json:"reactions"`
+}
+type Author struct {
+	Login             string `json:"login"`
+	ID                int    `json:"id"`
+	NodeID            string `json:"node_id"`
+	AvatarURL         string `json:"avatar_url"`
+	GravatarID        string `json:"gravatar_id"`
+	URL               string `json:"url"`
+	HTMLURL           string `json:"html_url"`
+	FollowersURL      string `json:"followers_url"`
+	FollowingURL      string `json:"following_url"`
+	GistsURL          string `json:"gists_url"`
+	StarredURL        string `json:"starred_url"`
+	SubscriptionsURL  string `json:"subscriptions_url"`
+	OrganizationsURL  string `json:"organizations_url"`
+	ReposURL          string `json:"repos_url"`
+	EventsURL         string `json:"events_url"`
+	ReceivedEventsURL string `json:"received_events_url"`
+	Type              string `json:"type"`
+	SiteAdmin         bool   `json:"site_admin"`
+}
+type Reactions struct {
+	URL        string `json:"url"`
+	TotalCount int    `json:"total_count"`
+	Laugh      int    `json:"laugh"`
+	Hooray     int    `json:"hooray"`
+	Confused   int    `json:"confused"`
+	Heart      int    `json:"heart"`
+	Rocket     int    `json:"rocket"`
+	Eyes       int    `json:"eyes"`
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFindChecksum(t *testing.T) {
+	sums := []byte("aaa  terraform_1.5.7_darwin_arm64.zip\nbbb *terraform_1.5.7_linux_amd64.zip\n")
+
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{"text mode entry", "terraform_1.5.7_darwin_arm64.zip", "aaa", false},
+		{"binary mode entry", "terraform_1.5.7_linux_amd64.zip", "bbb", false},
+		{"missing entry", "terraform_1.5.7_windows_amd64.zip", "", true},
+		{"prefix of an entry", "terraform_1.5.7_linux", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findChecksum(sums, tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("findChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.zip")
+	if err := os.WriteFile(path, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c.Logger = logrus.New()
+
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{"match", "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03", false},
+		{"match upper case", "5891B5B522D5DF086D0FF0B110FBD9D21BB4FC7163AF34D08286A2E846F6BE03", false},
+		{"mismatch", "0000000000000000000000000000000000000000000000000000000000000000", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyChecksum(path, tt.expected); (err != nil) != tt.wantErr {
+				t.Fatalf("verifyChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHashicorpKeyRing(t *testing.T) {
+	c.Logger = logrus.New()
+	c.PGPKeyFile = ""
+
+	keyring, err := hashicorpKeyRing()
+	if err != nil {
+		t.Fatalf("hashicorpKeyRing() error = %v", err)
+	}
+	if len(keyring) != 1 {
+		t.Fatalf("hashicorpKeyRing() returned %d entities, want 1", len(keyring))
+	}
+	if got := fmt.Sprintf("%X", keyring[0].PrimaryKey.Fingerprint); got != hashicorpKeyFingerprint {
+		t.Fatalf("hashicorpKeyRing() fingerprint = %s, want %s", got, hashicorpKeyFingerprint)
+	}
+}
@@ -1,106 +1,236 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
-	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-resty/resty/v2"
 )
 
-func listVersions(verMatch string, all bool) {
+const (
+	// hashicorpReleasesURL is the HashiCorp releases API for terraform.
+	hashicorpReleasesURL = "https://api.releases.hashicorp.com/v1/releases/terraform"
+	// hashicorpReleasesPageSize is the most releases the API returns in one page.
+	hashicorpReleasesPageSize = 20
+)
 
-	releaseTags, err := getTerraformVersions()
-	if err != nil {
-		c.Logger.WithError(err).Error("failed to read terraform releases")
-	}
+// listOptions are the flags of the versions command.
+type listOptions struct {
+	VerMatch   string
+	All        bool
+	Prerelease bool
+	Output     string
+	Installed  bool
+	GroupBy    string
+	Limit      int
+	Since      string
+	Reverse    bool
+}
 
-	if all {
-		for _, v := range releaseTags {
-			if len(verMatch) > 0 {
-				if strings.HasPrefix(v, verMatch) {
-					fmt.Printf("%s\n", v)
-				}
-			} else {
-				fmt.Printf("%s\n", v)
-			}
+func listVersions(opts listOptions) error {
+
+	var releases []HashicorpRelease
+	var releaseTags []string
+	var err error
+	if opts.Installed {
+		releaseTags, err = installedVersions()
+		if err != nil {
+			return err
 		}
 	} else {
-		justMinors(&releaseTags, verMatch)
+		releases, err = getTerraformReleases()
+		if err != nil {
+			return fmt.Errorf("%w: failed to read terraform releases: %w", ErrReleasesUnavailable, err)
+		}
+		releaseTags = releaseVersions(releases, opts.Prerelease)
 	}
-}
 
-func extractVersions(html []byte) []string {
-	var versions []string
-	re := regexp.MustCompile(`\/terraform\/([\w.-]+)\/`)
-	matches := re.FindAllSubmatch(html, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			versions = append(versions, string(match[1]))
+	if isVersionConstraint(opts.VerMatch) {
+		releaseTags, err = matchVersions(opts.VerMatch, releaseTags)
+		if err != nil {
+			return err
 		}
+		opts.VerMatch = ""
 	}
-	return versions
+
+	published := map[string]string{}
+	for _, release := range releases {
+		if !release.TimestampCreated.IsZero() {
+			published[release.Version] = release.TimestampCreated.Format(time.RFC3339)
+		}
+	}
+
+	selected, err := groupVersions(releaseTags, published, opts)
+	if err != nil {
+		return err
+	}
+	return printVersions(newVersionEntries(selected, published), opts.Output)
+}
+
+// isPrerelease reports whether TAG has a semver prerelease component, such as -rc.1 or -alpha.2.
+func isPrerelease(tag string) bool {
+	nv, err := semver.NewVersion(tag)
+	return err == nil && len(nv.Prerelease()) > 0
 }
 
-func justMinors(releaseTags *[]string, verMatch string) {
+// groupVersions filters RELEASETAGS by the prefix and --since date of OPTS and keeps the newest
+// version of each major or minor. The newest --limit versions are returned oldest first, or newest
+// first with --reverse. Tags that are not semver are skipped.
+func groupVersions(releaseTags []string, published map[string]string, opts listOptions) ([]string, error) {
+	groupBy := opts.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = "minor"
+		if opts.All {
+			groupBy = "none"
+		}
+	}
+	if groupBy != "major" && groupBy != "minor" && groupBy != "none" {
+		return nil, fmt.Errorf("unknown --group-by %q, use major, minor or none", groupBy)
+	}
 
-	minorRelease := map[string]semver.Version{}
-	for _, v := range *releaseTags {
+	var since time.Time
+	if len(opts.Since) > 0 {
+		var err error
+		since, err = parseSince(opts.Since)
+		if err != nil {
+			return nil, err
+		}
+		if len(published) == 0 {
+			return nil, fmt.Errorf("--since needs release dates, which are not known for these versions")
+		}
+	}
+
+	groups := map[string]*semver.Version{}
+	for _, v := range releaseTags {
+		if len(opts.VerMatch) > 0 && !strings.HasPrefix(v, opts.VerMatch) {
+			continue
+		}
 		nv, err := semver.NewVersion(v)
 		if err != nil {
-			c.Logger.Error(fmt.Sprintf("Error parsing SemVer for %s", v))
+			c.Logger.Debugf("skipping %s, not a semver version: %s", v, err)
+			continue
 		}
-		verKey := fmt.Sprintf("%d.%d", nv.Major(), nv.Minor())
-		processVer := true
-		if len(verMatch) > 0 {
-			if !strings.HasPrefix(v, verMatch) {
-				processVer = false
+		if !since.IsZero() {
+			publishedAt, err := time.Parse(time.RFC3339, published[v])
+			if err != nil || publishedAt.Before(since) {
+				continue
 			}
 		}
-		if processVer {
-			if _, ok := minorRelease[verKey]; ok {
-				if minorRelease[verKey].Patch() < nv.Patch() {
-					minorRelease[verKey] = *nv
-				}
-			} else {
-				// add
-				minorRelease[verKey] = *nv
-			}
+
+		verKey := v
+		switch groupBy {
+		case "major":
+			verKey = fmt.Sprintf("%d", nv.Major())
+		case "minor":
+			verKey = fmt.Sprintf("%d.%d", nv.Major(), nv.Minor())
+		}
+		if current, ok := groups[verKey]; !ok || nv.GreaterThan(current) {
+			groups[verKey] = nv
 		}
 	}
+
+	// newest first, equal versions with different tags are ordered by tag so the output is stable
 	vs := []*semver.Version{}
-	for _, v := range minorRelease {
-		vs = append(vs, &v)
+	for _, v := range groups {
+		vs = append(vs, v)
+	}
+	sort.Slice(vs, func(i, j int) bool {
+		if vs[i].Equal(vs[j]) {
+			return vs[i].Original() > vs[j].Original()
+		}
+		return vs[i].GreaterThan(vs[j])
+	})
+	if opts.Limit > 0 && len(vs) > opts.Limit {
+		vs = vs[:opts.Limit]
+	}
+	if !opts.Reverse {
+		slices.Reverse(vs)
 	}
 
-	sort.Sort(semver.Collection(vs))
-
-	// kubernetes/kubernetes uses a "v", so we should indicate so
+	tags := []string{}
 	for _, v := range vs {
-		fmt.Printf("%s\n", v)
+		tags = append(tags, v.Original())
 	}
+	return tags, nil
 }
 
-// GetGitHubReleases fetches all releases for the given owner and repo from GitHub.
-// curl -L \
-// -H "Accept: application/vnd.github+json" \
-// -H "X-GitHub-Api-Version: 2022-11-28" \
-// https://api.github.com/repos/kubernetes/kubernetes/releases
+// parseSince reads the --since date, either a day such as 2024-01-31 or an RFC 3339 time.
+func parseSince(since string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, since); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q, use a date such as 2024-01-31", since)
+	}
+	return t, nil
+}
+
+// getTerraformReleases returns the terraform releases, from the on-disk cache while it is fresh.
+func getTerraformReleases() ([]HashicorpRelease, error) {
+	return cachedFetch("releases", fetchTerraformReleases)
+}
+
+// getTerraformVersions returns every published terraform version, prereleases included.
 func getTerraformVersions() ([]string, error) {
-	client := resty.New()
-	url := "https://releases.hashicorp.com/terraform/"
-	resp, err := client.R().
-		Get(url)
+	releases, err := getTerraformReleases()
 	if err != nil {
 		return nil, err
 	}
+	return releaseVersions(releases, true), nil
+}
 
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+// releaseVersions returns the versions of RELEASES, leaving out prereleases unless asked for.
+func releaseVersions(releases []HashicorpRelease, prerelease bool) []string {
+	versions := []string{}
+	for _, release := range releases {
+		if (release.IsPrerelease || isPrerelease(release.Version)) && !prerelease {
+			continue
+		}
+		versions = append(versions, release.Version)
 	}
+	return versions
+}
+
+// fetchTerraformReleases pages through the HashiCorp releases API, which returns the newest releases
+// first and continues after the creation time of the last release of the previous page.
+func fetchTerraformReleases() ([]HashicorpRelease, error) {
+	client := resty.New()
+	releases := []HashicorpRelease{}
+	after := ""
+	for {
+		req := client.R().SetQueryParam("limit", strconv.Itoa(hashicorpReleasesPageSize))
+		if len(after) > 0 {
+			req.SetQueryParam("after", after)
+		}
+		resp, err := req.Get(hashicorpReleasesURL)
+		if err != nil {
+			return nil, err
+		}
 
-	tagNames := extractVersions(resp.Body())
-	return tagNames, nil
+		if resp.StatusCode() != 200 {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode())
+		}
+
+		var page []HashicorpRelease
+		if err := json.Unmarshal(resp.Body(), &page); err != nil {
+			return nil, fmt.Errorf("failed to decode the terraform releases: %w", err)
+		}
+		releases = append(releases, page...)
+
+		if len(page) < hashicorpReleasesPageSize {
+			return releases, nil
+		}
+		next := page[len(page)-1].TimestampCreated.Format(time.RFC3339Nano)
+		if next == after {
+			return releases, nil
+		}
+		after = next
+	}
 }
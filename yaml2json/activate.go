@@ -10,23 +10,43 @@ import (
 	"github.com/go-resty/resty/v2"
 )
 
-func activateVersion(ver string, binPath string, symLinkPath string) {
+func activateVersion(ver string, binPath string, symLinkPath string) error {
+
+	if len(ver) == 0 {
+		return fmt.Errorf("no version specified, use -v")
+	}
+	if isVersionConstraint(ver) {
+		resolved, err := resolveConstraint(ver)
+		if err != nil {
+			return err
+		}
+		c.Logger.Infof("Resolved %s to version %s of yaml2json", ver, resolved)
+		ver = resolved
+	}
+	ver = normalizeVersion(ver)
 
 	path := filepath.Join(binPath, fmt.Sprintf("yaml2json/%s/yaml2json", ver))
 	if !fileExists(path) {
+		if err := validateVersion(ver); err != nil {
+			return err
+		}
+
 		c.Logger.Infof("Downloading version %s of yaml2json to path %s", ver, path)
-		err := downloadArtifact(ver, path)
+		err := stageArtifact(path, func(stagedPath string) error {
+			return downloadArtifact(ver, stagedPath)
+		})
 		if err != nil {
-			c.Logger.WithError(err).Error("failed to download artifact")
+			return err
 		}
 	}
 
 	c.Logger.Infof("Activating version %s of yaml2json", ver)
 	err := changeFilePermissionsAndSymlink(path, symLinkPath)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to change perms and symlink")
+		return fmt.Errorf("%w: %w", ErrActivateFailed, err)
 	}
 
+	return nil
 }
 
 func fileExists(path string) bool {
@@ -38,7 +58,7 @@ func fileExists(path string) bool {
 func downloadArtifact(semver, path string) error {
 	targetOS := runtime.GOOS
 	if targetOS != "linux" && targetOS != "darwin" {
-		return fmt.Errorf("unsupported OS: %s", targetOS)
+		return fmt.Errorf("%w: unsupported OS: %s", ErrUnsupportedPlatform, targetOS)
 	}
 
 	targetArch := runtime.GOARCH
@@ -47,26 +67,30 @@ func downloadArtifact(semver, path string) error {
 	} else if targetArch == "arm64" {
 		targetArch = "arm"
 	} else {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	if targetOS == "darwin" && targetArch == "arm" {
-		return fmt.Errorf("unsupported architecture: %s", targetArch)
+		return fmt.Errorf("%w: unsupported architecture: %s", ErrUnsupportedPlatform, targetArch)
 	}
 
 	// https://github.com/bronze1man/yaml2json/releases/download/v1.3/yaml2json_darwin_amd64
-	url := fmt.Sprintf("https://github.com/bronze1man/yaml2json/releases/download/%s/yaml2json_%s_%s", semver, targetOS, targetArch)
+	url := fmt.Sprintf("%s/bronze1man/yaml2json/releases/download/%s/yaml2json_%s_%s", githubServerURL(), semver, targetOS, targetArch)
 
 	c.Logger.Tracef("downloading yaml2json from %s to %s", url, path)
 
 	client := resty.New()
-	resp, err := client.R().SetOutput(path).Get(url)
+	resp, err := githubAssetRequest(client, url).SetOutput(path).Get(url)
 	if err != nil {
-		return fmt.Errorf("error downloading yaml2json: %v", err)
+		return fmt.Errorf("%w: error downloading yaml2json: %w", ErrDownloadFailed, err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: yaml2json %s", ErrVersionNotFound, semver)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		return fmt.Errorf("error downloading yaml2json: status code %d", resp.StatusCode())
+		return fmt.Errorf("%w: error downloading yaml2json: status code %d", ErrDownloadFailed, resp.StatusCode())
 	}
 
 	return nil
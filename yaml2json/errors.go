@@ -0,0 +1,38 @@
+package main
+
+import "errors"
+
+// Errors returned by the commands, wrapped with the details of what failed. They are exported so the
+// host can tell them apart with errors.Is.
+var (
+	// ErrVersionNotFound is returned when the requested version is not published.
+	ErrVersionNotFound = errors.New("version not found")
+	// ErrUnsupportedPlatform is returned when there is no build for this OS or architecture.
+	ErrUnsupportedPlatform = errors.New("unsupported platform")
+	// ErrReleasesUnavailable is returned when the list of releases could not be fetched.
+	ErrReleasesUnavailable = errors.New("releases unavailable")
+	// ErrRateLimited is returned when the GitHub API rate limit is exhausted.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrDownloadFailed is returned when the artifact could not be downloaded.
+	ErrDownloadFailed = errors.New("download failed")
+	// ErrVerifyFailed is returned when the artifact does not match its published checksum or signature.
+	ErrVerifyFailed = errors.New("verification failed")
+	// ErrActivateFailed is returned when the binary could not be made executable or symlinked.
+	ErrActivateFailed = errors.New("activation failed")
+	// ErrOutdated is returned by the outdated command when a newer release than the active one exists.
+	ErrOutdated = errors.New("newer release available")
+)
+
+// ExitError asks the host to exit with Code rather than its usual failure code, the plugin never exits
+// the process itself. The host finds it with errors.As on the error returned by the command. Err is
+// the reason, the outdated command returns one wrapping ErrOutdated with Code 10.
+type ExitError struct {
+	Err  error
+	Code int
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// ExitCode returns Code, for hosts that check for an ExitCode() int method instead of the type.
+func (e *ExitError) ExitCode() int { return e.Code }